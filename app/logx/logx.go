@@ -68,6 +68,7 @@ type LogGate struct {
 	AppName      string               `json:"appname"`
 	CurrentLevel string               `json:"level"` // allowed log level: NONE,FATAL,ERROR,WARN,INFO,DEBUG
 	Filters      map[string]LogFilter `json:"filters"`
+	mu           sync.RWMutex         // guards Filters
 	fd           *os.File
 	fdCallTree   *os.File
 	configSub    string
@@ -243,6 +244,9 @@ func (l *LogGate) WriteCallTree(data string) {
  * directory. The filename is APPNAME.logfilter.
  */
 func (l *LogGate) SaveFilters() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	l.Filters["main"] = LogFilter{LogLevel: "debug", Specifically: ""}
 	l.Filters["lordofscripts/demo"] = LogFilter{LogLevel: "info", Specifically: "StructA,StructB"}
 
@@ -283,16 +287,50 @@ func (l *LogGate) LoadFilters() error {
 		return err
 	}
 
+	l.mu.Lock()
 	l.Filters = all.Filters
+	l.mu.Unlock()
 	return nil
 }
 
+/**
+ * Returns a copy of the Log Filters.
+ */
+func (l *LogGate) GetFilters() map[string]LogFilter {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	filters := make(map[string]LogFilter, len(l.Filters))
+	for name, filter := range l.Filters {
+		filters[name] = filter
+	}
+	return filters
+}
+
+/**
+ * Replaces the Log Filters at runtime, i.e. from the mlog admin
+ * handler. Safe to use while other goroutines are logging.
+ */
+func (l *LogGate) SetFilters(filters map[string]LogFilter) {
+	copied := make(map[string]LogFilter, len(filters))
+	for name, filter := range filters {
+		copied[name] = filter
+	}
+
+	l.mu.Lock()
+	l.Filters = copied
+	l.mu.Unlock()
+}
+
 /**
  * Checks if the packageName (GO package name format) is
  * black-listed. Black-listed packages do not produce log
  * output under LOGX.
  */
 func (l *LogGate) IsFiltered(packageName string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
 	if filter, exists := l.Filters[packageName]; !exists ||
 		exists && filter.LogLevel == "" {
 		return true
@@ -308,7 +346,9 @@ func (l *LogGate) IsFiltered(packageName string) bool {
  */
 func (l *LogGate) IsFilteredObject(packageName, objectName string) bool {
 	var blackListed bool = true
+	l.mu.RLock()
 	filter, exists := l.Filters[packageName]
+	l.mu.RUnlock()
 	if exists {
 		if filter.Specifically == "*" {
 			blackListed = false
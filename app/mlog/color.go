@@ -3,21 +3,25 @@
  *				  Copyright (C)2025 Lord of Scripts
  *							   goApp
  * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
- * Logging to Console with ANSI Color codes. But it always logs to the
- * console regardless of logging level, colorful simplicity.
+ * Logging to Console with ANSI Color codes. It logs to stdout (or any
+ * writer) regardless of the logger level, down to its own minimum
+ * level, with the colors of a Theme.
  *-----------------------------------------------------------------*/
 package mlog
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"sync"
 )
 
 /* ----------------------------------------------------------------
  *							G l o b a l s
  *-----------------------------------------------------------------*/
 
-var Console *ColorConsole = &ColorConsole{}
+var Console *ColorConsole = NewColorConsole(os.Stdout, LevelTrace, nil)
 
 const ( // some from https://azrael.digipen.edu/~mmead/www/mg/ansicolors/index.html
 	ColorBlack       Color = "\u001b[30m"
@@ -57,58 +61,172 @@ const ( // some from https://azrael.digipen.edu/~mmead/www/mg/ansicolors/index.h
 
 type Color string
 
+// ColorConsole prints colorized entries to a writer. It is safe for
+// concurrent use.
 type ColorConsole struct {
+	mu       sync.Mutex
+	w        io.Writer
+	minLevel LogLevel
+	theme    *Theme
 }
 
 /* ----------------------------------------------------------------
  *							C o n s t r u c t o r s
  *-----------------------------------------------------------------*/
 
+// NewColorConsole creates a console printing entries at minLevel and
+// above to w (stdout if nil) with the theme (Theme16 if nil).
+func NewColorConsole(w io.Writer, minLevel LogLevel, theme *Theme) *ColorConsole {
+	c := &ColorConsole{w: w, minLevel: minLevel}
+	c.SetTheme(theme)
+	return c
+}
+
 /* ----------------------------------------------------------------
  *							M e t h o d s
  *-----------------------------------------------------------------*/
 
+// SetOutput sets the writer of the console, stdout if nil.
+func (c *ColorConsole) SetOutput(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.w = w
+}
+
+// SetLevel sets the minimum level printed by the console.
+func (c *ColorConsole) SetLevel(level LogLevel) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.minLevel = level
+}
+
+// SetTheme sets the colors of the console, Theme16 if nil. A zero
+// Theme{} prints plain text.
+func (c *ColorConsole) SetTheme(theme *Theme) {
+	if theme == nil {
+		theme = &Theme16
+	}
+	copied := *theme
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.theme = &copied
+}
+
 // Trace level with format string
 func (c *ColorConsole) Trace(format string, args ...any) {
-	fmt.Print(ColorLightPurple, tagTRACE)
-	fmt.Printf(format, args...)
-	fmt.Print(ColorReset)
+	c.printf(LevelTrace, format, args)
 }
 
 // Debug level with format string
 func (c *ColorConsole) Debug(format string, args ...any) {
-	fmt.Print(ColorBrown, tagDEBUG)
-	fmt.Printf(format, args...)
-	fmt.Print(ColorReset)
+	c.printf(LevelDebug, format, args)
 }
 
 // Information level with format string
 func (c *ColorConsole) Info(format string, args ...any) {
-	fmt.Print(ColorGreen, tagINFO)
-	fmt.Printf(format, args...)
-	fmt.Print(ColorReset)
+	c.printf(LevelInfo, format, args)
 }
 
 // Warning level with format string
 func (c *ColorConsole) Warn(format string, args ...any) {
-	fmt.Print(ColorYellow, tagWARN)
-	fmt.Printf(format, args...)
-	fmt.Print(ColorReset)
+	c.printf(LevelWarning, format, args)
 }
 
 // Error level with format string
 func (c *ColorConsole) Error(format string, args ...any) {
-	fmt.Print(ColorPurple, tagERROR)
-	fmt.Printf(format, args...)
-	fmt.Print(ColorReset)
+	c.printf(LevelError, format, args)
 }
 
 // Fatal level with format string
 func (c *ColorConsole) Fatal(exitCode int, format string, args ...any) {
-	fmt.Print(ColorRed, tagFATAL)
-	fmt.Printf(format, args...)
-	fmt.Println("\t\t", face2, ColorReset)
-	os.Exit(exitCode)
+	c.printf(LevelFatal, format, args)
+	exit(exitCode)
+}
+
+// Trace level with message and variadic MLog tags
+func (c *ColorConsole) TraceT(message string, v ...ILogKeyValuePair) {
+	c.printt(LevelTrace, message, v)
+}
+
+// Debug level with message and variadic MLog tags
+func (c *ColorConsole) DebugT(message string, v ...ILogKeyValuePair) {
+	c.printt(LevelDebug, message, v)
+}
+
+// Information level with message and variadic MLog tags
+func (c *ColorConsole) InfoT(message string, v ...ILogKeyValuePair) {
+	c.printt(LevelInfo, message, v)
+}
+
+// Warning level with message and variadic MLog tags
+func (c *ColorConsole) WarnT(message string, v ...ILogKeyValuePair) {
+	c.printt(LevelWarning, message, v)
+}
+
+// Error level with message and variadic MLog tags
+func (c *ColorConsole) ErrorT(message string, v ...ILogKeyValuePair) {
+	c.printt(LevelError, message, v)
+}
+
+// Fatal level with message and variadic MLog tags
+func (c *ColorConsole) FatalT(exitCode int, message string, v ...ILogKeyValuePair) {
+	c.printt(LevelFatal, message, v)
+	exit(exitCode)
+}
+
+// prints the formatted text in the color of the level
+func (c *ColorConsole) printf(level LogLevel, format string, args []any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if level < c.minLevel {
+		return
+	}
+
+	text := fmt.Sprintf(format, args...)
+	var sb strings.Builder
+	style := c.theme.levelStyle(level)
+	sb.WriteString(style + levelTag(level) + text)
+	if level == LevelFatal && c.theme.FatalFace != "" {
+		sb.WriteString("\t\t " + c.theme.FatalFace + " " + c.theme.reset(style) + "\n")
+	} else {
+		sb.WriteString(c.theme.reset(style))
+	}
+	c.writer().Write([]byte(sb.String()))
+}
+
+// prints the message in the color of the level followed by the tags
+// with their keys highlighted
+func (c *ColorConsole) printt(level LogLevel, message string, tags []ILogKeyValuePair) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if level < c.minLevel {
+		return
+	}
+
+	_, message, _, rendered := formatEntry("", message, tags)
+	var sb strings.Builder
+	style := c.theme.levelStyle(level)
+	sb.WriteString(style + levelTag(level) + message + c.theme.reset(style))
+	c.theme.writeTags(&sb, rendered)
+	if level == LevelFatal && c.theme.FatalFace != "" {
+		sb.WriteString("\t\t " + style + c.theme.FatalFace + c.theme.reset(style))
+	}
+	sb.WriteString("\n")
+	c.writer().Write([]byte(sb.String()))
+}
+
+// the output writer, stdout by default
+func (c *ColorConsole) writer() io.Writer {
+	if c.w == nil {
+		return os.Stdout
+	}
+	return c.w
 }
 
 /*
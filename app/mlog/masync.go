@@ -0,0 +1,246 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Asynchronous log pipeline. Log entries are put in a bounded queue
+ * which is drained by a background goroutine so that the caller does
+ * not wait for the output. What happens when the queue is full is
+ * decided by the overflow policy.
+ *-----------------------------------------------------------------*/
+package mlog
+
+import (
+	"fmt"
+	"sync"
+)
+
+/* ----------------------------------------------------------------
+ *							G l o b a l s
+ *-----------------------------------------------------------------*/
+
+const (
+	// Queue overflow policy enumeration
+	OverflowBlock      OverflowPolicy = iota // the caller waits for room
+	OverflowDropOldest                       // the oldest queued entry is discarded
+	OverflowDropBelow                        // entries below AsyncOptions.KeepLevel are discarded, others wait
+)
+
+const defaultQueueSize int = 1024
+
+/* ----------------------------------------------------------------
+ *							T y p e s
+ *-----------------------------------------------------------------*/
+
+type OverflowPolicy int
+
+// AsyncOptions configures the asynchronous pipeline of a logger.
+type AsyncOptions struct {
+	QueueSize int            // bounded queue capacity, 0 uses the default (1024)
+	Policy    OverflowPolicy // what to do when the queue is full
+	KeepLevel LogLevel       // with OverflowDropBelow, the lowest level that is never dropped
+}
+
+// bounded ring buffer of log records drained by a goroutine
+type asyncQueue struct {
+	mu      sync.Mutex
+	changed *sync.Cond // signaled on every put, take and idle transition
+	opts    AsyncOptions
+	ring    []*record
+	head    int
+	count   int
+	busy    bool // the drainer is writing a batch
+	closed  bool
+	dropped int
+	done    chan struct{}
+}
+
+/* ----------------------------------------------------------------
+ *							C o n s t r u c t o r s
+ *-----------------------------------------------------------------*/
+
+// starts a queue whose records are written with write
+func newAsyncQueue(opts AsyncOptions, write func(*record)) *asyncQueue {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = defaultQueueSize
+	}
+
+	q := &asyncQueue{
+		opts: opts,
+		ring: make([]*record, opts.QueueSize),
+		done: make(chan struct{}),
+	}
+	q.changed = sync.NewCond(&q.mu)
+
+	go q.drain(write)
+	return q
+}
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// put queues the record applying the overflow policy. It returns
+// false if the queue has been stopped and the record was not taken.
+func (q *asyncQueue) put(r *record) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for !q.closed && q.count == len(q.ring) {
+		switch {
+		case q.opts.Policy == OverflowDropOldest:
+			q.ring[q.head] = nil
+			q.head = (q.head + 1) % len(q.ring)
+			q.count--
+			q.dropped++
+
+		case q.opts.Policy == OverflowDropBelow && r.level < q.opts.KeepLevel:
+			q.dropped++
+			return true
+
+		default:
+			q.changed.Wait()
+		}
+	}
+
+	if q.closed {
+		return false
+	}
+
+	q.ring[(q.head+q.count)%len(q.ring)] = r
+	q.count++
+	q.changed.Broadcast()
+	return true
+}
+
+// discards is true, counting it as dropped, if a record of the level
+// would be dropped right now. It lets the caller skip the rendering,
+// and the lazy tags, of the entries the queue has no room for.
+func (q *asyncQueue) discards(level LogLevel) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.opts.Policy == OverflowDropBelow && level < q.opts.KeepLevel && !q.closed && q.count == len(q.ring) {
+		q.dropped++
+		return true
+	}
+	return false
+}
+
+// flush waits until every queued record has been written
+func (q *asyncQueue) flush() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for q.count > 0 || q.busy {
+		q.changed.Wait()
+	}
+}
+
+// stop drains the queue and terminates the background goroutine.
+// Records put afterwards are rejected.
+func (q *asyncQueue) stop() {
+	q.mu.Lock()
+	q.closed = true
+	q.changed.Broadcast()
+	q.mu.Unlock()
+
+	<-q.done
+}
+
+// the background goroutine: takes all queued records at once and
+// writes them outside the lock, followed by a notice when records
+// were dropped.
+func (q *asyncQueue) drain(write func(*record)) {
+	defer close(q.done)
+	batch := make([]*record, 0, len(q.ring))
+
+	for {
+		q.mu.Lock()
+		for q.count == 0 && q.dropped == 0 && !q.closed {
+			q.changed.Wait()
+		}
+		if q.count == 0 && q.dropped == 0 && q.closed {
+			q.mu.Unlock()
+			return
+		}
+
+		batch = batch[:0]
+		for ; q.count > 0; q.count-- {
+			batch = append(batch, q.ring[q.head])
+			q.ring[q.head] = nil
+			q.head = (q.head + 1) % len(q.ring)
+		}
+		dropped := q.dropped
+		q.dropped = 0
+		q.busy = true
+		q.changed.Broadcast()
+		q.mu.Unlock()
+
+		for _, r := range batch {
+			write(r)
+		}
+		if dropped > 0 {
+			write(newRecord(LevelWarning, fmt.Sprintf("mlog: %d log entries dropped (queue full)", dropped)))
+		}
+
+		q.mu.Lock()
+		q.busy = false
+		q.changed.Broadcast()
+		q.mu.Unlock()
+	}
+}
+
+// SetAsync switches the logger to the asynchronous pipeline. A zero
+// QueueSize uses the default capacity. Calling it again replaces the
+// queue after draining the previous one.
+func (l *Logger) SetAsync(opts AsyncOptions) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if old := l.async.Swap(newAsyncQueue(opts, l.write)); old != nil {
+		old.stop()
+	}
+}
+
+// SetSync switches the logger back to synchronous output after
+// draining the queue.
+func (l *Logger) SetSync() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if old := l.async.Swap(nil); old != nil {
+		old.stop()
+	}
+}
+
+// Flush writes the pending repeat summary and waits until all queued
+// entries have been written.
+func (l *Logger) Flush() {
+	if d := l.dedup.Load(); d != nil {
+		d.flush()
+	}
+	if q := l.async.Load(); q != nil {
+		q.flush()
+	}
+}
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// SetAsync switches the default logger to the asynchronous pipeline.
+func SetAsync(opts AsyncOptions) {
+	std.SetAsync(opts)
+}
+
+// SetSync switches the default logger back to synchronous output.
+func SetSync() {
+	std.SetSync()
+}
+
+// Flush waits until all the queued entries of the default logger
+// have been written.
+func Flush() {
+	std.Flush()
+}
@@ -116,7 +116,8 @@ func (c *CallerInfo) StringF(format string) string {
 	out = strings.Replace(out, "%F", c.function+IFUNC, 1)
 	out = strings.Replace(out, "%M", c.function+IFUNC, 1)
 	out = strings.Replace(out, "%L", strconv.Itoa(c.lineno), 1)
-	if strings.Contains(out, "%p") && strings.LastIndexByte(c.packageN, '/') > 0 {
+	if strings.Contains(out, "%p") {
+		// the last element of the package path
 		pname := c.packageN[strings.LastIndexByte(c.packageN, '/')+1:]
 		out = strings.Replace(out, "%p", pname, 1)
 	}
@@ -139,46 +140,44 @@ func RetrieveCallerInfo(frame FrameNr) *CallerInfo {
 	pc, fileName, lineNo, ok := runtime.Caller(frame)
 
 	if ok {
-		funcName := runtime.FuncForPC(pc).Name()
-		//fmt.Println(funcName)
-		lastSlash := strings.LastIndexByte(funcName, '/')
-		if lastSlash < 0 {
-			lastSlash = 0
-		}
-		lastDot := strings.LastIndexByte(funcName[lastSlash:], '.') + lastSlash
-
-		//var title string
-		var pkg, stru, fun string
-		//fmt.Printf("RCI ***%s\n", funcName)
-		if idx := strings.IndexByte(funcName[:lastDot], '.'); idx > -1 {
-			// Value struct:  Event
-			// Pointer to struct: (*Event)
-			// Package Init(): init
-			stru = funcName[idx+1 : lastDot]
-			pkg = funcName[:idx]
-			if stru == "init" {
-				// A package.init() comes as stru:init func:0
-				stru = ""
-			}
-			//title = "Method "
-		} else {
-			stru = ""
-			pkg = funcName[:lastDot]
-			//title = "Func   "
-		}
-
-		fun = strings.Trim(funcName[lastDot+1:], " ")
-		if fun == "0" {
-			fun = "init"
-		}
-		ci := &CallerInfo{packageN: pkg, structure: stru, function: fun, filename: fileName, lineno: lineNo}
-
-		//fmt.Printf("  Package: %s\n", funcName[:firstDot])
-		//fmt.Printf("  Package: %s\n", pkg)
-		//fmt.Printf("  Object : %s\n", stru)
-		//fmt.Printf("  %s: %s\n", title, fun)
-		return ci
+		return newCallerInfo(runtime.FuncForPC(pc).Name(), fileName, lineNo)
 	}
 
 	return nil
 }
+
+// CallerInfoAt returns the caller info of a program counter as given
+// by runtime.Callers(), or nil if it is unknown.
+func CallerInfoAt(pc uintptr) *CallerInfo {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	if frame.Function == "" {
+		return nil
+	}
+	return newCallerInfo(frame.Function, frame.File, frame.Line)
+}
+
+// parses the fully qualified function name into package, structure
+// and function.
+func newCallerInfo(funcName, fileName string, lineNo int) *CallerInfo {
+	//fmt.Println(funcName)
+	// Value struct:  Event
+	// Pointer to struct: (*Event)
+	// Package Init(): init
+	pkg, stru, fun := splitFuncName(funcName)
+	if stru == "init" {
+		// A package.init() comes as stru:init func:0
+		stru = ""
+	}
+
+	fun = strings.Trim(fun, " ")
+	if fun == "0" {
+		fun = "init"
+	}
+	ci := &CallerInfo{packageN: pkg, structure: stru, function: fun, filename: fileName, lineno: lineNo}
+
+	//fmt.Printf("  Package: %s\n", funcName[:firstDot])
+	//fmt.Printf("  Package: %s\n", pkg)
+	//fmt.Printf("  Object : %s\n", stru)
+	//fmt.Printf("  %s: %s\n", title, fun)
+	return ci
+}
@@ -0,0 +1,63 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Named catheters. Besides the catheter of SetCatheterFile() a logger
+ * may have any number of named ones (protocol dumps, cipher traces,
+ * UI events...), each with its own file. They are only available in
+ * the development (mlog) build.
+ *-----------------------------------------------------------------*/
+package mlog
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+/* ----------------------------------------------------------------
+ *							G l o b a l s
+ *-----------------------------------------------------------------*/
+
+const (
+	// environment variable with the named catheters for CaesarX as
+	// name=path pairs, ">>" before the path appends to the file:
+	// "wire=/tmp/wire.log,cipher=>>/tmp/cipher.log"
+	LOG_CATHETERS_ENV string = "LOG_CATHETERS_CX"
+)
+
+const (
+	// Catheter file policy enumeration
+	CatheterTruncate CatheterMode = iota // start with an empty file
+	CatheterAppend                       // keep the previous runs
+)
+
+/* ----------------------------------------------------------------
+ *							T y p e s
+ *-----------------------------------------------------------------*/
+
+// CatheterMode tells what happens to an existing catheter file
+type CatheterMode int
+
+// CatheterChannel is a named catheter. It is shared by a logger and
+// all its children; the tags bound with With() are not included. Its
+// entries are dropped while it has no file.
+type CatheterChannel struct {
+	name   string
+	mu     sync.Mutex
+	file   io.WriteCloser
+	active atomic.Bool // it has a file
+}
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// Name returns the name of the catheter.
+func (c *CatheterChannel) Name() string {
+	if c == nil {
+		return ""
+	}
+	return c.name
+}
@@ -0,0 +1,179 @@
+//go:build mlog
+// +build mlog
+
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Named catheters of the development build.
+ *-----------------------------------------------------------------*/
+package mlog
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// Enabled reports whether the catheter has a file. Use it to guard
+// expensive dumps.
+func (c *CatheterChannel) Enabled() bool {
+	return c.active.Load()
+}
+
+// Print writes an unformatted entry to the catheter file, if any.
+func (c *CatheterChannel) Print(message string, v ...ILogKeyValuePair) {
+	if !c.active.Load() {
+		return
+	}
+
+	line, _, _, _ := formatEntry(tagCATHE, message, v)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.file != nil {
+		io.WriteString(c.file, line+"\n")
+	}
+}
+
+// Printf writes a formatted message to the catheter file, if any.
+func (c *CatheterChannel) Printf(format string, v ...any) {
+	if c.active.Load() {
+		c.Print(fmt.Sprintf(format, v...))
+	}
+}
+
+// replaces the file, closing the previous one
+func (c *CatheterChannel) setFile(file io.WriteCloser) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var err error = nil
+	if c.file != nil {
+		err = closeLogFile(c.file)
+	}
+	c.file = file
+	c.active.Store(file != nil)
+	return err
+}
+
+// Catheter returns the named catheter of the logger, which drops its
+// entries until OpenCatheter() or SetCatheters() gives it a file.
+func (l *Logger) Catheter(name string) *CatheterChannel {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.catheter(name)
+}
+
+// OpenCatheter (re)opens the file of the named catheter. With
+// CatheterTruncate an existing file starts empty, with CatheterAppend
+// it is appended to. The rotation options apply.
+func (l *Logger) OpenCatheter(name, filename string, mode CatheterMode) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	file, err := openLogFile(filename, mode == CatheterTruncate, l.rotation)
+	if err != nil {
+		return err
+	}
+	return l.catheter(name).setFile(file)
+}
+
+// CloseCatheter closes the file of the named catheter, which then
+// drops its entries.
+func (l *Logger) CloseCatheter(name string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if c, ok := l.catheters[name]; ok {
+		return c.setFile(nil)
+	}
+	return nil
+}
+
+// SetCatheters opens the catheters of a spec like the value of
+// LOG_CATHETERS_ENV: "wire=/tmp/wire.log,ui=>>/tmp/ui.log". The files
+// are truncated unless the path starts with ">>".
+func (l *Logger) SetCatheters(spec string) error {
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		name, filename, _ := strings.Cut(item, "=")
+		mode := CatheterTruncate
+		if strings.HasPrefix(filename, ">>") {
+			filename, mode = filename[2:], CatheterAppend
+		}
+		name, filename = strings.TrimSpace(name), strings.TrimSpace(filename)
+		if name == "" || filename == "" {
+			return fmt.Errorf("mlog: bad catheter %q, expected name=path", item)
+		}
+
+		if err := l.OpenCatheter(name, filename, mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// the named catheter, created if needed. Must be called with the
+// configuration lock held.
+func (l *Logger) catheter(name string) *CatheterChannel {
+	c, ok := l.catheters[name]
+	if !ok {
+		if l.catheters == nil {
+			l.catheters = make(map[string]*CatheterChannel)
+		}
+		c = &CatheterChannel{name: name}
+		l.catheters[name] = c
+	}
+	return c
+}
+
+// closes the files of the named catheters. Must be called with the
+// configuration lock held.
+func (c *loggerCore) closeCatheters() error {
+	var first error = nil
+	for _, channel := range c.catheters {
+		if err := channel.setFile(nil); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// Catheter returns the named catheter of the default logger, i.e.
+// mlog.Catheter("wire").Print("sent", mlog.Hex("Frame", frame))
+func Catheter(name string) *CatheterChannel {
+	return std.Catheter(name)
+}
+
+// OpenCatheter (re)opens the file of a named catheter of the default
+// logger. See Logger.OpenCatheter().
+func OpenCatheter(name, filename string, mode CatheterMode) error {
+	return std.OpenCatheter(name, filename, mode)
+}
+
+// CloseCatheter closes the file of a named catheter of the default
+// logger.
+func CloseCatheter(name string) error {
+	return std.CloseCatheter(name)
+}
+
+// SetCatheters opens the named catheters of the default logger listed
+// in the spec. See Logger.SetCatheters().
+func SetCatheters(spec string) error {
+	return std.SetCatheters(spec)
+}
@@ -0,0 +1,75 @@
+//go:build !mlog
+// +build !mlog
+
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Named catheters of the release build, where they are stubbed out.
+ *-----------------------------------------------------------------*/
+package mlog
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// The "catheter" feature is not enabled.
+func (c *CatheterChannel) Enabled() bool {
+	return false
+}
+
+// The "catheter" feature is not enabled.
+func (c *CatheterChannel) Print(message string, v ...ILogKeyValuePair) {}
+
+// The "catheter" feature is not enabled.
+func (c *CatheterChannel) Printf(format string, v ...any) {}
+
+// The "catheter" feature is not enabled.
+func (l *Logger) Catheter(name string) *CatheterChannel {
+	return nil
+}
+
+// The "catheter" feature is not enabled.
+func (l *Logger) OpenCatheter(name, filename string, mode CatheterMode) error {
+	return nil
+}
+
+// The "catheter" feature is not enabled.
+func (l *Logger) CloseCatheter(name string) error {
+	return nil
+}
+
+// The "catheter" feature is not enabled.
+func (l *Logger) SetCatheters(spec string) error {
+	return nil
+}
+
+// The "catheter" feature is not enabled.
+func (c *loggerCore) closeCatheters() error {
+	return nil
+}
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// The "catheter" feature is not enabled.
+func Catheter(name string) *CatheterChannel {
+	return nil
+}
+
+// The "catheter" feature is not enabled.
+func OpenCatheter(name, filename string, mode CatheterMode) error {
+	return nil
+}
+
+// The "catheter" feature is not enabled.
+func CloseCatheter(name string) error {
+	return nil
+}
+
+// The "catheter" feature is not enabled.
+func SetCatheters(spec string) error {
+	return nil
+}
@@ -0,0 +1,180 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Logger configuration from code, a JSON file, environment variables
+ * with an application prefix and command line flags. Settings merge
+ * in that order, later ones win:
+ *
+ *	cfg, err := mlog.LoadConfig("myapp.json")
+ *	flags := mlog.RegisterFlags(flag.CommandLine)
+ *	flag.Parse()
+ *	cfg = cfg.Merge(mlog.ConfigFromEnv("MYAPP")).Merge(*flags)
+ *	err = mlog.Configure(cfg)
+ *-----------------------------------------------------------------*/
+package mlog
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"strings"
+)
+
+/* ----------------------------------------------------------------
+ *							T y p e s
+ *-----------------------------------------------------------------*/
+
+// Config holds the settings of a logger. Empty fields are left as
+// they are.
+type Config struct {
+	Level     string `json:"level,omitempty"`     // level spec, see SetLevelSpec()
+	File      string `json:"file,omitempty"`      // log file (appended)
+	Format    string `json:"format,omitempty"`    // layout template, see ParseLayout()
+	Rotate    string `json:"rotate,omitempty"`    // rotation spec, see ParseRotation()
+	Catheters string `json:"catheters,omitempty"` // named catheters, see SetCatheters()
+}
+
+// ConfigError lists the invalid settings of a Config.
+type ConfigError struct {
+	Errs []error
+}
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// implements error
+func (e *ConfigError) Error() string {
+	msgs := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		msgs[i] = strings.TrimPrefix(err.Error(), "mlog: ")
+	}
+	return "mlog: " + strings.Join(msgs, "; ")
+}
+
+// Merge returns the configuration with the non-empty settings of over
+// replacing its own.
+func (c Config) Merge(over Config) Config {
+	merge := func(own *string, other string) {
+		if other != "" {
+			*own = other
+		}
+	}
+	merge(&c.Level, over.Level)
+	merge(&c.File, over.File)
+	merge(&c.Format, over.Format)
+	merge(&c.Rotate, over.Rotate)
+	merge(&c.Catheters, over.Catheters)
+	return c
+}
+
+// Configure applies the non-empty settings of the configuration. The
+// invalid ones are skipped and reported as a *ConfigError. The
+// rotation applies to the files opened afterwards, including the log
+// file of this configuration, which replaces the current one.
+func (l *Logger) Configure(cfg Config) error {
+	var errs []error = nil
+
+	if cfg.Rotate != "" {
+		if opts, err := ParseRotation(cfg.Rotate); err != nil {
+			errs = append(errs, err)
+		} else {
+			l.SetRotation(opts)
+		}
+	}
+	if cfg.Level != "" {
+		if err := l.SetLevelSpec(cfg.Level); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if cfg.Format != "" {
+		if layout, err := ParseLayout(cfg.Format); err != nil {
+			errs = append(errs, err)
+		} else {
+			l.SetLayout(layout)
+		}
+	}
+	if cfg.File != "" {
+		l.mu.Lock()
+		if l.logFile == nil || l.logName != cfg.File {
+			if err := l.switchLogFile(cfg.File); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		l.mu.Unlock()
+	}
+	if cfg.Catheters != "" {
+		if err := l.SetCatheters(cfg.Catheters); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return &ConfigError{Errs: errs}
+	}
+	return nil
+}
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// Configure applies the configuration to the default logger. See
+// Logger.Configure().
+func Configure(cfg Config) error {
+	return std.Configure(cfg)
+}
+
+// LoadConfig reads a configuration from a JSON file with the keys
+// level, file, format, rotate and catheters.
+func LoadConfig(filename string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return cfg, err
+	}
+
+	err = json.Unmarshal(data, &cfg)
+	return cfg, err
+}
+
+// ConfigFromEnv reads the configuration from the environment variables
+// of an application, i.e. with prefix MYAPP: MYAPP_LOG_LEVEL,
+// MYAPP_LOG_FILE, MYAPP_LOG_FORMAT, MYAPP_LOG_ROTATE and
+// MYAPP_LOG_CATHETERS. An empty prefix reads LOG_LEVEL, etc.
+func ConfigFromEnv(prefix string) Config {
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+	return Config{
+		Level:     os.Getenv(prefix + "LOG_LEVEL"),
+		File:      os.Getenv(prefix + "LOG_FILE"),
+		Format:    os.Getenv(prefix + "LOG_FORMAT"),
+		Rotate:    os.Getenv(prefix + "LOG_ROTATE"),
+		Catheters: os.Getenv(prefix + "LOG_CATHETERS"),
+	}
+}
+
+// RegisterFlags adds the -log-level, -log-file and -log-format flags to
+// the flag set. The returned configuration holds their values once the
+// flags are parsed.
+func RegisterFlags(fs *flag.FlagSet) *Config {
+	cfg := &Config{}
+	fs.StringVar(&cfg.Level, "log-level", "", "log level spec, i.e. warn or error,*/crypto=trace")
+	fs.StringVar(&cfg.File, "log-file", "", "append the log to this file instead of stderr")
+	fs.StringVar(&cfg.Format, "log-format", "", "log line layout, i.e. '{time:RFC3339} {level} {msg} {tags}'")
+	return cfg
+}
+
+// the configuration of the CaesarX environment variables
+func legacyEnvConfig() Config {
+	return Config{
+		Level:     os.Getenv(LOG_LEVEL_ENV),
+		File:      os.Getenv(LOG_FILE_ENV),
+		Format:    os.Getenv(LOG_LAYOUT_ENV),
+		Rotate:    os.Getenv(LOG_ROTATE_ENV),
+		Catheters: os.Getenv(LOG_CATHETERS_ENV),
+	}
+}
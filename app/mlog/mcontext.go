@@ -0,0 +1,167 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Context-carried tags. Tags stored in a context.Context (request ID,
+ * user, operation...) are appended automatically by the *Ctx logging
+ * functions so that entries of concurrent requests can be told apart.
+ *-----------------------------------------------------------------*/
+package mlog
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+/* ----------------------------------------------------------------
+ *							G l o b a l s
+ *-----------------------------------------------------------------*/
+
+// the tag key of the correlation ID
+const CorrelationKey string = "CID"
+
+/* ----------------------------------------------------------------
+ *							T y p e s
+ *-----------------------------------------------------------------*/
+
+// key of the mlog data in a context
+type contextKey struct{}
+
+// the mlog data carried by a context
+type contextData struct {
+	tags []ILogKeyValuePair
+	cid  string
+}
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// WithContext returns a child logger which adds the tags carried by
+// the context to every entry.
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	return l.With(ContextTags(ctx)...)
+}
+
+// Warning level with message, context tags and variadic MLog tags.
+func (l *Logger) WarnCtx(ctx context.Context, message string, v ...ILogKeyValuePair) {
+	l.logt(LevelWarning, message, withContextTags(ctx, v))
+}
+
+// Error level with message, context tags and variadic MLog tags.
+func (l *Logger) ErrorCtx(ctx context.Context, message string, v ...ILogKeyValuePair) {
+	l.logt(LevelError, message, withContextTags(ctx, v))
+}
+
+// Fatal level with message, context tags and variadic MLog tags.
+// it terminates execution with exitCode.
+func (l *Logger) FatalCtx(ctx context.Context, exitCode int, message string, v ...ILogKeyValuePair) {
+	l.logt(LevelFatal, message, withContextTags(ctx, v))
+	l.Flush()
+	exit(exitCode)
+}
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// NewContext returns a copy of ctx carrying the given tags after
+// those already carried by ctx.
+func NewContext(ctx context.Context, tags ...ILogKeyValuePair) context.Context {
+	return withContextData(ctx, "", tags)
+}
+
+// FromContext returns a child of the default logger which adds the
+// tags carried by the context to every entry.
+func FromContext(ctx context.Context) *Logger {
+	return std.WithContext(ctx)
+}
+
+// ContextTags returns the tags carried by the context, if any.
+func ContextTags(ctx context.Context) []ILogKeyValuePair {
+	if ctx == nil {
+		return nil
+	}
+	if data, ok := ctx.Value(contextKey{}).(*contextData); ok {
+		return data.tags
+	}
+	return nil
+}
+
+// WithCorrelationID returns a copy of ctx carrying a new correlation
+// ID, logged as the CID tag, unless ctx already has one.
+func WithCorrelationID(ctx context.Context) context.Context {
+	if CorrelationID(ctx) != "" {
+		return ctx
+	}
+
+	cid := NewCorrelationID()
+	return withContextData(ctx, cid, []ILogKeyValuePair{String(CorrelationKey, cid)})
+}
+
+// CorrelationID returns the correlation ID carried by the context or
+// an empty string.
+func CorrelationID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if data, ok := ctx.Value(contextKey{}).(*contextData); ok {
+		return data.cid
+	}
+	return ""
+}
+
+// NewCorrelationID generates a short random ID (8 hex digits) to
+// correlate the log entries of a request.
+func NewCorrelationID() string {
+	var id [4]byte
+	rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// a copy of ctx carrying the mlog data of its parent plus the tags
+// and the correlation ID (if not empty).
+func withContextData(ctx context.Context, cid string, tags []ILogKeyValuePair) context.Context {
+	data := contextData{}
+	if parent, ok := ctx.Value(contextKey{}).(*contextData); ok {
+		data = *parent
+	}
+	data.tags = append(append([]ILogKeyValuePair(nil), data.tags...), tags...)
+	if cid != "" {
+		data.cid = cid
+	}
+	return context.WithValue(ctx, contextKey{}, &data)
+}
+
+// the context tags followed by the caller's tags
+func withContextTags(ctx context.Context, v []ILogKeyValuePair) []ILogKeyValuePair {
+	ctxTags := ContextTags(ctx)
+	if len(ctxTags) == 0 {
+		return v
+	}
+	return append(append(make([]ILogKeyValuePair, 0, len(ctxTags)+len(v)), ctxTags...), v...)
+}
+
+/* - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ *			N o n - P r i v i l e g e d   L e v e l s
+ *- - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -*/
+
+// Warning level with message, context tags and variadic MLog tags.
+func WarnCtx(ctx context.Context, message string, v ...ILogKeyValuePair) {
+	std.logt(LevelWarning, message, withContextTags(ctx, v))
+}
+
+// Error level with message, context tags and variadic MLog tags.
+func ErrorCtx(ctx context.Context, message string, v ...ILogKeyValuePair) {
+	std.logt(LevelError, message, withContextTags(ctx, v))
+}
+
+// Fatal level with message, context tags and variadic MLog tags.
+// it terminates execution with exitCode.
+func FatalCtx(ctx context.Context, exitCode int, message string, v ...ILogKeyValuePair) {
+	std.logt(LevelFatal, message, withContextTags(ctx, v))
+	std.Flush()
+	exit(exitCode)
+}
@@ -0,0 +1,231 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Panic recovery. A deferred Recover() (or a goroutine started with
+ * GoSafe) logs the panic at Fatal level with its stack, writes a crash
+ * report with the stack of all goroutines, the last log entries and
+ * the names of the environment variables, closes the log files and
+ * then exits or re-panics.
+ *-----------------------------------------------------------------*/
+package mlog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+/* ----------------------------------------------------------------
+ *							G l o b a l s
+ *-----------------------------------------------------------------*/
+
+const (
+	// log entries kept for the crash report
+	recentSize int = 50
+	// exit code after a panic, like the Go runtime's
+	crashExitCode int = 2
+)
+
+var crashOptions atomic.Pointer[CrashOptions]
+
+/* ----------------------------------------------------------------
+ *							T y p e s
+ *-----------------------------------------------------------------*/
+
+// CrashOptions configure what Recover() does after logging a panic.
+type CrashOptions struct {
+	ReportDir string // where crash reports go, "" for the temp dir
+	NoReport  bool   // do not write a crash report
+	ExitCode  int    // exit code, 0 for 2
+	RePanic   bool   // re-panic instead of exiting
+}
+
+// the last entries written by a logger, formatted only for a report
+type recentLines struct {
+	lines [recentSize]recentLine
+	next  int
+	full  bool
+}
+
+// the parts of a kept entry
+type recentLine struct {
+	time   time.Time
+	prefix string
+	line   string
+}
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// keeps the entry, dropping the oldest one when full
+func (r *recentLines) add(t time.Time, prefix, line string) {
+	r.lines[r.next] = recentLine{t, prefix, line}
+	r.next = (r.next + 1) % recentSize
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// the kept lines, oldest first
+func (r *recentLines) snapshot() []string {
+	kept := r.lines[:r.next]
+	if r.full {
+		kept = append(append([]recentLine(nil), r.lines[r.next:]...), kept...)
+	}
+
+	lines := make([]string, len(kept))
+	for i, l := range kept {
+		lines[i] = l.time.Format("2006-01-02 15:04:05.000 ") + l.prefix + l.line
+	}
+	return lines
+}
+
+// the last entries written, oldest first
+func (c *loggerCore) recentEntries() []string {
+	c.outMu.Lock()
+	defer c.outMu.Unlock()
+
+	return c.recent.snapshot()
+}
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// SetCrashOptions changes what Recover() does after logging a panic.
+func SetCrashOptions(opts CrashOptions) {
+	crashOptions.Store(&opts)
+}
+
+// Recover must be deferred. On panic it logs the panic at Fatal level
+// with its stack, writes the crash report, closes the log and catheter
+// files and then exits or re-panics as set by SetCrashOptions().
+//
+//	func main() {
+//		defer mlog.Recover()
+//		...
+//	}
+func Recover() {
+	if v := recover(); v != nil {
+		handlePanic(v)
+	}
+}
+
+// GoSafe runs fn in a new goroutine guarded by Recover().
+func GoSafe(fn func()) {
+	go func() {
+		defer Recover()
+		fn()
+	}()
+}
+
+// logs and reports the panic, then exits or re-panics
+func handlePanic(v any) {
+	opts := CrashOptions{}
+	if current := crashOptions.Load(); current != nil {
+		opts = *current
+	}
+
+	pcs := make([]uintptr, maxStackDepth)
+	pcs = panickingStack(pcs[:runtime.Callers(3, pcs)])
+	tags := []ILogKeyValuePair{Any("Panic", v)}
+	report := ""
+	if !opts.NoReport {
+		report = crashReportName(opts.ReportDir)
+		tags = append(tags, String("Report", report))
+	}
+	tags = append(tags, &kvRendered{"Stack=" + formatStack(pcs)})
+
+	std.output(LevelFatal, true, "panic recovered", tags)
+	std.Flush()
+	// after the entry so that it is the last one in the report
+	if report != "" {
+		if err := writeCrashReport(report, v); err != nil {
+			std.output(LevelError, true, "crash report not written", []ILogKeyValuePair{Err(err)})
+		}
+	}
+	std.Close()
+
+	if opts.RePanic {
+		panic(v)
+	}
+	if opts.ExitCode == 0 {
+		opts.ExitCode = crashExitCode
+	}
+	exit(opts.ExitCode)
+}
+
+// the frames of the panicking function and its callers, without the
+// deferred calls and the runtime's panic machinery.
+func panickingStack(pcs []uintptr) []uintptr {
+	for i, pc := range pcs {
+		if fn := runtime.FuncForPC(pc); fn != nil && fn.Name() == "runtime.gopanic" {
+			return pcs[i+1:]
+		}
+	}
+	return pcs
+}
+
+// the name of the crash report file in dir
+func crashReportName(dir string) string {
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, fmt.Sprintf("crash-%s-%s-%d.txt", crashProgram(), time.Now().Format("20060102T150405"), os.Getpid()))
+}
+
+// the program name without extension
+func crashProgram() string {
+	return strings.TrimSuffix(filepath.Base(os.Args[0]), filepath.Ext(os.Args[0]))
+}
+
+// writes the crash report file
+func writeCrashReport(filename string, v any) error {
+	now := time.Now()
+	program := crashProgram()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "CRASH REPORT %s (pid %d)\n", program, os.Getpid())
+	fmt.Fprintf(&sb, "Time   : %s\n", now.Format(time.RFC3339Nano))
+	fmt.Fprintf(&sb, "Runtime: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&sb, "Command: %s\n", strings.Join(os.Args, " "))
+	fmt.Fprintf(&sb, "Panic  : %v (%T)\n", v, v)
+
+	sb.WriteString("\n== Goroutines ==\n")
+	buf := make([]byte, 64*1024)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			sb.Write(buf[:n])
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	sb.WriteString("\n== Last Entries ==\n")
+	for _, line := range std.recentEntries() {
+		sb.WriteString(terminated(line))
+	}
+
+	// the names only, the values may be tokens or passwords
+	sb.WriteString("\n== Environment ==\n")
+	names := make([]string, 0)
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sb.WriteString(name + "\n")
+	}
+
+	return os.WriteFile(filename, []byte(sb.String()), 0600)
+}
@@ -0,0 +1,118 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Repeated-message suppression. Consecutive identical entries (same
+ * level, message and tags) are collapsed into the first one followed
+ * by a "last message repeated N times" summary, which is written when
+ * a different entry arrives, when the window elapses or on Close.
+ *-----------------------------------------------------------------*/
+package mlog
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+/* ----------------------------------------------------------------
+ *							T y p e s
+ *-----------------------------------------------------------------*/
+
+// the deduplicating stage in front of the output
+type dedupStage struct {
+	mu      sync.Mutex
+	window  time.Duration
+	now     func() time.Time // the logger's clock
+	emit    func(*record)
+	last    *record // the last entry written
+	repeats int     // identical entries suppressed since then
+	first   time.Time
+	timer   *time.Timer
+}
+
+/* ----------------------------------------------------------------
+ *							C o n s t r u c t o r s
+ *-----------------------------------------------------------------*/
+
+func newDedupStage(window time.Duration, now func() time.Time, emit func(*record)) *dedupStage {
+	return &dedupStage{window: window, now: now, emit: emit}
+}
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// passes the record on unless it repeats the last one
+func (d *dedupStage) put(r *record) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.last != nil && d.last.main == r.main && d.last.line == r.line {
+		if d.repeats == 0 {
+			d.first = d.last.time
+			d.timer = time.AfterFunc(d.window, d.flush)
+		}
+		d.repeats++
+		d.last = r
+		return
+	}
+
+	d.summarize()
+	d.last = r
+	d.emit(r)
+}
+
+// writes the pending summary, if any
+func (d *dedupStage) flush() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.summarize()
+}
+
+// writes the summary of the suppressed repeats. Must be called with
+// the lock held.
+func (d *dedupStage) summarize() {
+	if d.repeats == 0 {
+		return
+	}
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+
+	elapsed := d.last.time.Sub(d.first).Round(time.Millisecond)
+	summary := newRecord(d.last.level, fmt.Sprintf("last message repeated %d times over %s", d.repeats, elapsed))
+	summary.time = d.now()
+	summary.main = d.last.main
+	d.repeats = 0
+	d.emit(summary)
+}
+
+// SetDedup collapses consecutive identical entries. Their summary is
+// written at the latest when the window elapses. A zero window turns
+// the suppression off.
+func (l *Logger) SetDedup(window time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var stage *dedupStage = nil
+	if window > 0 {
+		stage = newDedupStage(window, l.now, l.enqueue)
+	}
+	if old := l.dedup.Swap(stage); old != nil {
+		old.flush()
+	}
+}
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// SetDedup collapses consecutive identical entries of the default
+// logger. See Logger.SetDedup().
+func SetDedup(window time.Duration) {
+	std.SetDedup(window)
+}
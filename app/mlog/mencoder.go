@@ -0,0 +1,291 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Sink encoders for the machines: logfmt and JSON lines. Besides the
+ * time, level, message and call site every tag becomes a field of its
+ * own, typed when the tag knows its type, so the same call sites feed
+ * a human on the terminal and a log collector:
+ *
+ *	time=2025-06-01T10:00:00.000Z level=info msg="user logged in" caller=login.go:42 User=alice Admin=true
+ *	{"time":"2025-06-01T10:00:00.000Z","level":"info","msg":"user logged in","caller":"login.go:42","User":"alice","Admin":true}
+ *-----------------------------------------------------------------*/
+package mlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+/* ----------------------------------------------------------------
+ *							G l o b a l s
+ *-----------------------------------------------------------------*/
+
+const (
+	// timestamp of the encoded entries
+	encoderTimeFormat string = "2006-01-02T15:04:05.000Z07:00"
+)
+
+// Encoder enumeration
+const (
+	EncodeHuman  Encoder = iota // the usual line, see WithTimeFormat() and WithLayout()
+	EncodeLogfmt                // key=value pairs, quoted where needed
+	EncodeJSON                  // one JSON object per line
+)
+
+var (
+	// keys of the fields every entry may have, tags get "tag." before
+	// them so a JSON object has no duplicate keys
+	encoderKeys = map[string]bool{
+		"time": true, "level": true, "msg": true,
+		"prefix": true, "caller": true, "func": true,
+	}
+)
+
+/* ----------------------------------------------------------------
+ *							T y p e s
+ *-----------------------------------------------------------------*/
+
+// Encoder is how a sink writes its entries.
+type Encoder int
+
+// a field of an encoded entry
+type encodedField struct {
+	key   string
+	value any
+}
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// implements fmt.Stringer
+func (e Encoder) String() string {
+	switch e {
+	case EncodeHuman:
+		return "human"
+	case EncodeLogfmt:
+		return "logfmt"
+	case EncodeJSON:
+		return "json"
+	default:
+		return "Encoder(" + strconv.Itoa(int(e)) + ")"
+	}
+}
+
+// the entry as a logfmt or JSON line, without newline
+func (e Encoder) encode(prefix string, r *record) []byte {
+	fields := encodedFields(prefix, r)
+	if e == EncodeJSON {
+		return encodeJSON(fields)
+	}
+	return encodeLogfmt(fields)
+}
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// WithEncoder makes the sink write logfmt or JSON lines instead of the
+// usual ones. They carry their own timestamp, so the time format and
+// the layout of the sink are ignored.
+func WithEncoder(enc Encoder) SinkOption {
+	return func(s *Sink) {
+		s.encoder = enc
+	}
+}
+
+// the fields of a record: time, level, message, prefix (if any),
+// call site (if known) and the tags, see encoderKeys
+func encodedFields(prefix string, r *record) []encodedField {
+	fields := make([]encodedField, 0, 5+len(r.tags))
+	fields = append(fields,
+		encodedField{"time", r.time.Format(encoderTimeFormat)},
+		encodedField{"level", levelName(r.level)},
+		encodedField{"msg", r.message})
+	if prefix = strings.TrimSpace(prefix); prefix != "" {
+		fields = append(fields, encodedField{"prefix", prefix})
+	}
+	if r.pc != 0 {
+		// nil for a pc the runtime cannot resolve
+		if ci := CallerInfoAt(r.pc); ci != nil {
+			fields = append(fields,
+				encodedField{"caller", ci.SourceInfo()},
+				encodedField{"func", ci.ObjectInfo()})
+		}
+	}
+	for i, t := range r.tags {
+		key, value := tagField(t, r.tagText[i])
+		if encoderKeys[key] {
+			key = "tag." + key
+		}
+		fields = append(fields, encodedField{key, value})
+	}
+	return fields
+}
+
+// the key and typed value of a tag. Tags of other types, lazy ones
+// (already evaluated once) and redacted ones give their rendered value
+// as a string.
+func tagField(t ILogKeyValuePair, rendered string) (string, any) {
+	switch k := t.(type) {
+	case *kvString:
+		return k.k, k.v
+	case *kvRune:
+		return k.k, string(k.v)
+	case *kvInt:
+		return k.k, k.v
+	case *kvBool:
+		return k.k, k.v
+	case *kvYesNo:
+		return k.k, k.v
+	case *kvByte:
+		return k.k, int(k.v)
+	case *kvInt64:
+		return k.k, k.v
+	case *kvUint64:
+		return k.k, k.v
+	case *kvFloat64:
+		return k.k, k.v
+	case *kvDuration:
+		return k.k, k.v
+	case *kvTime:
+		return k.k, k.v
+	case *kvStrings:
+		return k.k, k.v
+	case *kvInts:
+		return k.k, k.v
+	case *kvAny:
+		return k.k, k.v
+	case *kvError:
+		if k.v == nil {
+			return "Error", nil
+		}
+	}
+
+	key, value, found := splitRendered(rendered)
+	if !found {
+		key = "tag"
+	}
+	return key, value
+}
+
+// the fields as a JSON object. Values that JSON cannot hold (NaN,
+// channels...) are written as their %+v string.
+func encodeJSON(fields []encodedField) []byte {
+	b := make([]byte, 0, 256)
+	b = append(b, '{')
+	for i, f := range fields {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		key, _ := jsonMarshal(f.key)
+		b = append(b, key...)
+		b = append(b, ':')
+
+		value := f.value
+		switch v := value.(type) {
+		case time.Duration:
+			value = v.String()
+		case time.Time:
+			value = v.Format(time.RFC3339Nano)
+		case error:
+			value = v.Error()
+		}
+		encoded, err := jsonMarshal(value)
+		if err != nil {
+			encoded, _ = jsonMarshal(fmt.Sprintf("%+v", value))
+		}
+		b = append(b, encoded...)
+	}
+	return append(b, '}')
+}
+
+// like json.Marshal() but leaves <, > and & alone
+func jsonMarshal(v any) ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
+
+// the fields as a logfmt line
+func encodeLogfmt(fields []encodedField) []byte {
+	var sb strings.Builder
+	for i, f := range fields {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(logfmtKey(f.key))
+		sb.WriteByte('=')
+		sb.WriteString(logfmtValue(f.value))
+	}
+	return []byte(sb.String())
+}
+
+// a logfmt key: no blanks, quotes, equal signs or control characters
+func logfmtKey(key string) string {
+	key = strings.Map(func(c rune) rune {
+		if c <= ' ' || c == '=' || c == '"' || !unicode.IsPrint(c) {
+			return '_'
+		}
+		return c
+	}, key)
+	if key == "" {
+		return "tag"
+	}
+	return key
+}
+
+// a logfmt value, quoted if empty or if it has blanks, quotes, equal
+// signs or unprintable characters. Lists are comma separated.
+func logfmtValue(value any) string {
+	var s string
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		s = v
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case time.Duration:
+		return v.String()
+	case []string:
+		s = strings.Join(v, ",")
+	case []int:
+		nums := make([]string, len(v))
+		for i, n := range v {
+			nums[i] = strconv.Itoa(n)
+		}
+		return strings.Join(nums, ",")
+	default:
+		s = fmt.Sprintf("%+v", v)
+	}
+
+	needsQuotes := s == "" || strings.IndexFunc(s, func(c rune) bool {
+		return c <= ' ' || c == '=' || c == '"' || c == '\\' || !unicode.IsPrint(c)
+	}) >= 0
+	if needsQuotes {
+		return strconv.Quote(s)
+	}
+	return s
+}
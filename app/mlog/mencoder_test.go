@@ -0,0 +1,63 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Tests of the logfmt and JSON encoders: the fixed fields, tags named
+ * like them and call sites the runtime cannot resolve.
+ *-----------------------------------------------------------------*/
+package mlog
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// a record as the logger would build it
+func encoderRecord(pc uintptr, tags ...ILogKeyValuePair) *record {
+	r := &record{
+		time:    time.Date(2025, time.June, 1, 10, 0, 0, 0, time.UTC),
+		level:   LevelWarning,
+		message: "disk full",
+		tags:    tags,
+		pc:      pc,
+	}
+	for _, t := range tags {
+		r.tagText = append(r.tagText, t.String())
+	}
+	return r
+}
+
+func TestEncoderCollidingTags(t *testing.T) {
+	r := encoderRecord(0, String("time", "later"), String("msg", "shadow"), Int("Used", 97))
+
+	want := `{"time":"2025-06-01T10:00:00.000Z","level":"warning","msg":"disk full","prefix":"app:",` +
+		`"tag.time":"later","tag.msg":"shadow","Used":97}`
+	got := EncodeJSON.encode("app: ", r)
+	if string(got) != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+	var object map[string]any
+	if err := json.Unmarshal(got, &object); err != nil || object["msg"] != "disk full" {
+		t.Errorf("decoded %v, %v", object, err)
+	}
+
+	want = `time=2025-06-01T10:00:00.000Z level=warning msg="disk full" tag.time=later tag.msg=shadow Used=97`
+	if got := string(EncodeLogfmt.encode("", r)); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestEncoderUnknownCaller(t *testing.T) {
+	// not the address of any function
+	got := string(EncodeJSON.encode("", encoderRecord(1)))
+	want := `{"time":"2025-06-01T10:00:00.000Z","level":"warning","msg":"disk full"}`
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
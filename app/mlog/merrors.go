@@ -0,0 +1,178 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Error chain expansion for the Err tags. The unwrap chain and the
+ * members of joined errors are listed with their type and message.
+ * Errors wrapped with WrapE() also carry the location (and stack)
+ * where they were created.
+ *-----------------------------------------------------------------*/
+package mlog
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+/* ----------------------------------------------------------------
+ *							G l o b a l s
+ *-----------------------------------------------------------------*/
+
+const (
+	// frames kept by WrapE() and ErrStack()
+	maxStackDepth int = 32
+	// guards against cyclic unwrap chains
+	maxErrorDepth int = 16
+)
+
+var _ error = (*TracedError)(nil)
+
+/* ----------------------------------------------------------------
+ *							T y p e s
+ *-----------------------------------------------------------------*/
+
+// TracedError wraps an error with the location and call stack where
+// it was wrapped by WrapE().
+type TracedError struct {
+	err   error
+	at    *CallerInfo
+	stack []uintptr
+}
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// implements the error interface with the message of the wrapped error
+func (e *TracedError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error for errors.Is() and errors.As()
+func (e *TracedError) Unwrap() error {
+	return e.err
+}
+
+// At returns where the error was wrapped.
+func (e *TracedError) At() *CallerInfo {
+	return e.at
+}
+
+// Stack returns the call stack where the error was wrapped, one frame
+// per line.
+func (e *TracedError) Stack() string {
+	return formatStack(e.stack)
+}
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// WrapE records the caller's location and stack in the error so that
+// the Err tags can tell where it was created. It returns nil for nil.
+//
+//	if err != nil {
+//		return mlog.WrapE(err)
+//	}
+func WrapE(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(2, pcs) // from the caller of WrapE()
+	return &TracedError{err: err, at: RetrieveCallerInfo(FRAMENR_CALLER), stack: pcs[:n]}
+}
+
+// describes the error chain: each cause as type=>message separated
+// by " <- ", joined errors within brackets.
+func describeError(err error) string {
+	var sb strings.Builder
+	writeErrorChain(&sb, err, 0)
+	return sb.String()
+}
+
+// writes err and its causes
+func writeErrorChain(sb *strings.Builder, err error, depth int) {
+	for i := 0; err != nil; i++ {
+		if depth+i >= maxErrorDepth {
+			sb.WriteString(" <- ...")
+			return
+		}
+		if i > 0 {
+			sb.WriteString(" <- ")
+		}
+
+		switch e := err.(type) {
+		case *TracedError:
+			if e.at != nil {
+				fmt.Fprintf(sb, "%T@%s", e, e.at)
+			} else {
+				fmt.Fprintf(sb, "%T", e)
+			}
+			err = e.err
+			continue
+
+		case interface{ Unwrap() []error }:
+			fmt.Fprintf(sb, "%T=>[", e)
+			for j, member := range e.Unwrap() {
+				if j > 0 {
+					sb.WriteString(" ; ")
+				}
+				writeErrorChain(sb, member, depth+i+1)
+			}
+			sb.WriteString("]")
+			return
+
+		default:
+			fmt.Fprintf(sb, "%T=>%s", e, oneLine(e.Error()))
+		}
+
+		if wrapper, ok := err.(interface{ Unwrap() error }); ok {
+			err = wrapper.Unwrap()
+		} else {
+			err = nil
+		}
+	}
+}
+
+// the innermost stack recorded by WrapE() in the error chain, if any
+func tracedStack(err error) []uintptr {
+	var stack []uintptr = nil
+	for depth := 0; err != nil && depth < maxErrorDepth; depth++ {
+		if traced, ok := err.(*TracedError); ok {
+			stack = traced.stack
+		}
+		wrapper, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			break
+		}
+		err = wrapper.Unwrap()
+	}
+	return stack
+}
+
+// one "\n\tat function (file:line)" line per frame
+func formatStack(pcs []uintptr) string {
+	var sb strings.Builder
+	if len(pcs) == 0 {
+		return ""
+	}
+
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&sb, "\n\tat %s (%s:%d)", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return sb.String()
+}
+
+// multi-line messages (i.e. joined errors) on a single line
+func oneLine(s string) string {
+	return strings.ReplaceAll(s, "\n", "; ")
+}
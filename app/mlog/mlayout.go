@@ -0,0 +1,328 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Line layouts. By default an entry is written as the timestamp (on
+ * stderr), prefix, level tag, message and tags. A layout template
+ * rearranges them and picks the timestamp format, time zone and level
+ * labels, i.e.
+ *
+ *	{time:RFC3339Nano} {level:long} {caller:%p.%F#%L} {msg} {tags}
+ *-----------------------------------------------------------------*/
+package mlog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+/* ----------------------------------------------------------------
+ *							G l o b a l s
+ *-----------------------------------------------------------------*/
+
+const (
+	// environment variable with the layout template for CaesarX
+	LOG_LAYOUT_ENV string = "LOG_LAYOUT_CX"
+
+	// timestamp of {time} and {utc} without a format
+	defaultLayoutTime string = "2006-01-02 15:04:05"
+	// caller of {caller} without a format
+	defaultLayoutCaller string = "%B"
+)
+
+// Layout field enumeration
+const (
+	fieldText    layoutField = iota // literal text
+	fieldTime                       // local time
+	fieldUTC                        // UTC time
+	fieldElapsed                    // seconds since the application started
+	fieldLevel
+	fieldCaller
+	fieldMessage
+	fieldTags
+	fieldPrefix
+)
+
+var (
+	// when the application started, for {elapsed}
+	startTime = time.Now()
+
+	// the named timestamp formats
+	timeFormats = map[string]string{
+		"ANSIC":       time.ANSIC,
+		"RFC822":      time.RFC822,
+		"RFC1123":     time.RFC1123,
+		"RFC3339":     time.RFC3339,
+		"RFC3339Nano": time.RFC3339Nano,
+		"Kitchen":     time.Kitchen,
+		"Stamp":       time.Stamp,
+		"StampMilli":  time.StampMilli,
+		"StampMicro":  time.StampMicro,
+		"DateTime":    "2006-01-02 15:04:05",
+		"DateOnly":    "2006-01-02",
+		"TimeOnly":    "15:04:05",
+	}
+
+	// the level labels of {level:long}
+	longLevelLabels = []string{"TRACE", "DEBUG", "INFO", "WARN", "ERROR", "FATAL"}
+)
+
+/* ----------------------------------------------------------------
+ *							T y p e s
+ *-----------------------------------------------------------------*/
+
+type layoutField int
+
+// a field of a layout with its format, or literal text
+type layoutPart struct {
+	field  layoutField
+	format string
+}
+
+// Layout is a parsed layout template. These fields are recognized:
+//
+//	{time} {time:FORMAT}  local time, FORMAT is a time.Layout or a name
+//	                      like RFC3339Nano, StampMilli or DateTime
+//	{utc} {utc:FORMAT}    the same in UTC
+//	{elapsed}             seconds since the application started
+//	{level} {level:long}  [DBG] or DEBUG, or custom labels from Trace
+//	                      to Fatal: {level:T,D,I,W,E,F}
+//	{caller:FORMAT}       the call site, FORMAT as in CallerInfo.StringF()
+//	{msg} {tags} {prefix} the message, the tags and the logger prefix
+//
+// Use {{ for a literal brace. Without {prefix} the prefix goes first.
+type Layout struct {
+	template string
+	parts    []layoutPart
+	labels   [LevelFatal - LevelTrace + 1]string
+	caller   bool // it has a {caller} field
+	prefix   bool // it has a {prefix} field
+}
+
+/* ----------------------------------------------------------------
+ *							C o n s t r u c t o r s
+ *-----------------------------------------------------------------*/
+
+// ParseLayout parses a layout template, see Layout.
+func ParseLayout(template string) (*Layout, error) {
+	l := &Layout{template: template}
+	for level := LevelTrace; level <= LevelFatal; level++ {
+		tag := levelTag(level)
+		l.labels[level-LevelTrace] = tag[:len(tag)-1]
+	}
+
+	var text strings.Builder
+	for rest := template; rest != ""; {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			text.WriteString(rest)
+			break
+		}
+		text.WriteString(rest[:open])
+		rest = rest[open+1:]
+		if strings.HasPrefix(rest, "{") {
+			text.WriteString("{")
+			rest = rest[1:]
+			continue
+		}
+
+		end := strings.IndexByte(rest, '}')
+		if end < 0 {
+			return nil, fmt.Errorf("mlog: unclosed field in layout %q", template)
+		}
+		part, err := l.parseField(rest[:end])
+		if err != nil {
+			return nil, err
+		}
+		rest = rest[end+1:]
+
+		if text.Len() > 0 {
+			l.parts = append(l.parts, layoutPart{field: fieldText, format: text.String()})
+			text.Reset()
+		}
+		l.parts = append(l.parts, part)
+	}
+	if text.Len() > 0 {
+		l.parts = append(l.parts, layoutPart{field: fieldText, format: text.String()})
+	}
+
+	return l, nil
+}
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// implements fmt.Stringer, the template
+func (l *Layout) String() string {
+	return l.template
+}
+
+// parses the text between the braces of a field
+func (l *Layout) parseField(spec string) (layoutPart, error) {
+	name, format, hasFormat := strings.Cut(spec, ":")
+	part := layoutPart{format: format}
+	switch name {
+	case "time", "utc":
+		part.field = fieldTime
+		if name == "utc" {
+			part.field = fieldUTC
+		}
+		if named, ok := timeFormats[format]; ok {
+			part.format = named
+		} else if format == "" {
+			part.format = defaultLayoutTime
+		}
+
+	case "elapsed":
+		part.field = fieldElapsed
+
+	case "level":
+		part.field = fieldLevel
+		if err := l.setLabels(format); err != nil {
+			return part, err
+		}
+
+	case "caller":
+		part.field = fieldCaller
+		if !hasFormat {
+			part.format = defaultLayoutCaller
+		}
+		l.caller = true
+
+	case "msg":
+		part.field = fieldMessage
+
+	case "tags":
+		part.field = fieldTags
+
+	case "prefix":
+		part.field = fieldPrefix
+		l.prefix = true
+
+	default:
+		return part, fmt.Errorf("mlog: unknown layout field {%s}", spec)
+	}
+	return part, nil
+}
+
+// sets the level labels: short ([DBG]), long (DEBUG) or one per level
+func (l *Layout) setLabels(spec string) error {
+	var labels []string
+	switch spec {
+	case "", "short":
+		return nil
+	case "long":
+		labels = longLevelLabels
+	default:
+		labels = strings.Split(spec, ",")
+		if len(labels) != len(l.labels) {
+			return fmt.Errorf("mlog: {level:%s} needs %d labels, Trace to Fatal", spec, len(l.labels))
+		}
+	}
+	copy(l.labels[:], labels)
+	return nil
+}
+
+// the label of the level
+func (l *Layout) label(level LogLevel) string {
+	if level < LevelTrace || level > LevelFatal {
+		level = LevelFatal
+	}
+	return l.labels[level-LevelTrace]
+}
+
+// renders the record, colorized with the theme if not nil. Trailing
+// blanks (of empty fields) are trimmed.
+func (l *Layout) format(prefix string, r *record, theme *Theme) string {
+	var sb strings.Builder
+	if !l.prefix {
+		sb.WriteString(prefix)
+	}
+
+	for _, part := range l.parts {
+		switch part.field {
+		case fieldText:
+			sb.WriteString(part.format)
+
+		case fieldTime:
+			sb.WriteString(r.time.Local().Format(part.format))
+
+		case fieldUTC:
+			sb.WriteString(r.time.UTC().Format(part.format))
+
+		case fieldElapsed:
+			fmt.Fprintf(&sb, "%.3f", r.time.Sub(startTime).Seconds())
+
+		case fieldLevel:
+			if theme == nil {
+				sb.WriteString(l.label(r.level))
+			} else {
+				style := theme.levelStyle(r.level)
+				sb.WriteString(style + l.label(r.level) + theme.reset(style))
+			}
+
+		case fieldCaller:
+			var ci *CallerInfo = nil
+			if r.pc != 0 {
+				ci = CallerInfoAt(r.pc)
+			}
+			if ci != nil {
+				sb.WriteString(ci.StringF(part.format))
+			} else {
+				sb.WriteString("-")
+			}
+
+		case fieldMessage:
+			sb.WriteString(r.message)
+
+		case fieldTags:
+			var tags strings.Builder
+			tagTheme := theme
+			if tagTheme == nil {
+				tagTheme = &Theme{}
+			}
+			tagTheme.writeTags(&tags, r.tagText)
+			sb.WriteString(strings.TrimPrefix(tags.String(), " "))
+
+		case fieldPrefix:
+			sb.WriteString(prefix)
+		}
+	}
+
+	return strings.TrimRight(sb.String(), " \t")
+}
+
+// SetLayout writes the main output with the layout, or the default
+// one if nil. The layout includes the timestamp, if any, so the stderr
+// output is no longer stamped.
+func (l *Logger) SetLayout(layout *Layout) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.outMu.Lock()
+	l.layout = layout
+	l.outMu.Unlock()
+	l.updateCaller()
+}
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// SetLayout sets the layout of the default logger's main output. See
+// Logger.SetLayout().
+func SetLayout(layout *Layout) {
+	std.SetLayout(layout)
+}
+
+// WithLayout writes the lines of a sink with the layout instead of
+// the default one. The layout includes the timestamp, if any, so
+// WithTimeFormat() is ignored.
+func WithLayout(layout *Layout) SinkOption {
+	return func(s *Sink) {
+		s.layout = layout
+	}
+}
@@ -0,0 +1,265 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Per-package log level overrides. A level spec is a comma-separated
+ * list with an optional default level and package=level pairs, i.e.
+ * "error,github.com/acme/app/crypto=trace" where the package may
+ * contain '*' wildcards. The package of each call site is resolved
+ * once and cached by its program counter so the lookup stays cheap.
+ *-----------------------------------------------------------------*/
+package mlog
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+/* ----------------------------------------------------------------
+ *							G l o b a l s
+ *-----------------------------------------------------------------*/
+
+const (
+	// frames from levelFor() up to the application call site
+	// levelFor < enabled < logs|logf|logt < Warn|Logger.Warn < caller
+	framesToCallSite FrameNr = 5
+
+	// cached marker for call sites that match no rule
+	noOverride LogLevel = -1
+)
+
+/* ----------------------------------------------------------------
+ *							T y p e s
+ *-----------------------------------------------------------------*/
+
+// LevelOverride is the level of the packages matching a pattern, see
+// SetLevelSpec().
+type LevelOverride struct {
+	Package string   // full package name or pattern with '*' wildcards
+	Level   LogLevel // minimum level of those packages
+}
+
+// a single package=level override. The pattern is either a full
+// package name or contains '*' wildcards which match any sequence
+// of characters (slashes included).
+type levelRule struct {
+	pattern string
+	level   LogLevel
+}
+
+// the parsed per-package overrides with its call site cache
+type levelSpec struct {
+	rules []levelRule
+	cache sync.Map // uintptr (pc) => LogLevel
+}
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// the override level for the call site at the given frame or
+// noOverride if no rule matches its package.
+func (s *levelSpec) levelFor(frame FrameNr) LogLevel {
+	var pcs [1]uintptr
+	if runtime.Callers(frame, pcs[:]) == 0 {
+		return noOverride
+	}
+
+	if cached, ok := s.cache.Load(pcs[0]); ok {
+		return cached.(LogLevel)
+	}
+
+	level := noOverride
+	if pif := RetrievePackageInfo(frame); pif != nil {
+		level = s.match(pif.Package)
+	}
+	s.cache.Store(pcs[0], level)
+	return level
+}
+
+// the override level for a call site known by its program counter,
+// as given by runtime.Callers(), or noOverride.
+func (s *levelSpec) levelForPC(pc uintptr) LogLevel {
+	if cached, ok := s.cache.Load(pc); ok {
+		return cached.(LogLevel)
+	}
+
+	level := noOverride
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	if frame.Function != "" {
+		level = s.match(packageOfFunction(frame.Function))
+	}
+	s.cache.Store(pc, level)
+	return level
+}
+
+// the lowest level of all the overrides
+func (s *levelSpec) lowest() LogLevel {
+	lowest := LogLevel(noSinkLevel)
+	for _, r := range s.rules {
+		if r.level < lowest {
+			lowest = r.level
+		}
+	}
+	return lowest
+}
+
+// an exact package match wins over wildcards, otherwise the first
+// matching wildcard rule in the spec is used.
+func (s *levelSpec) match(pkg string) LogLevel {
+	level := noOverride
+	for _, r := range s.rules {
+		if r.pattern == pkg {
+			return r.level
+		}
+		if level == noOverride && strings.Contains(r.pattern, "*") && wildcardMatch(r.pattern, pkg) {
+			level = r.level
+		}
+	}
+	return level
+}
+
+// SetLevelSpec sets the minimum level and per-package overrides from
+// a level spec such as "error,github.com/acme/app/crypto=trace,*/ui=info".
+// If the spec has no default level the current one is kept.
+func (l *Logger) SetLevelSpec(spec string) error {
+	level, hasLevel, rules, err := parseLevelSpec(spec)
+	if err != nil {
+		return err
+	}
+
+	if hasLevel {
+		l.SetLevel(level)
+	}
+	if len(rules) == 0 {
+		l.spec.Store(nil)
+	} else {
+		l.spec.Store(&levelSpec{rules: rules})
+	}
+	return nil
+}
+
+// LevelOverrides returns the per-package overrides in spec order.
+func (l *Logger) LevelOverrides() []LevelOverride {
+	overrides := make([]LevelOverride, 0)
+	if spec := l.spec.Load(); spec != nil {
+		for _, rule := range spec.rules {
+			overrides = append(overrides, LevelOverride{rule.pattern, rule.level})
+		}
+	}
+	return overrides
+}
+
+// SetLevelOverrides replaces the per-package overrides, none removes
+// them. An exact package match wins over wildcards, otherwise the first
+// matching pattern is used.
+func (l *Logger) SetLevelOverrides(overrides []LevelOverride) {
+	if len(overrides) == 0 {
+		l.spec.Store(nil)
+		return
+	}
+
+	rules := make([]levelRule, len(overrides))
+	for i, o := range overrides {
+		rules[i] = levelRule{pattern: o.Package, level: o.Level}
+	}
+	l.spec.Store(&levelSpec{rules: rules})
+}
+
+// moves the level by delta steps within the levels of this build and
+// returns the previous and the new level.
+func (l *Logger) stepLevel(delta int) (LogLevel, LogLevel) {
+	for {
+		old := l.level.Load()
+		level := LogLevel(old) + LogLevel(delta)
+		if level < buildLevel {
+			level = buildLevel
+		}
+		if level > LevelFatal {
+			level = LevelFatal
+		}
+		if l.level.CompareAndSwap(old, int32(level)) {
+			return LogLevel(old), level
+		}
+	}
+}
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// SetLevelSpec sets the level and per-package overrides of the
+// default logger. See Logger.SetLevelSpec().
+func SetLevelSpec(spec string) error {
+	return std.SetLevelSpec(spec)
+}
+
+// parse a level spec into its default level and package overrides.
+func parseLevelSpec(spec string) (LogLevel, bool, []levelRule, error) {
+	var level LogLevel = defaultLevel
+	var hasLevel bool = false
+	rules := make([]levelRule, 0)
+
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		pkg, lvl, isRule := strings.Cut(item, "=")
+		if !isRule {
+			var err error
+			if level, err = ParseLevel(item); err != nil {
+				return level, hasLevel, nil, err
+			}
+			hasLevel = true
+			continue
+		}
+
+		pkg = strings.TrimSpace(pkg)
+		if pkg == "" {
+			return level, hasLevel, nil, fmt.Errorf("mlog: missing package in level spec item %q", item)
+		}
+		ruleLevel, err := ParseLevel(lvl)
+		if err != nil {
+			return level, hasLevel, nil, fmt.Errorf("mlog: unknown level %q for package %q", strings.TrimSpace(lvl), pkg)
+		}
+		rules = append(rules, levelRule{pattern: pkg, level: ruleLevel})
+	}
+
+	return level, hasLevel, rules, nil
+}
+
+// MatchPackage reports whether the package matches a pattern of a
+// level spec: either the full package name or one with '*' wildcards,
+// which match any sequence of characters (slashes included).
+func MatchPackage(pattern, pkg string) bool {
+	if strings.Contains(pattern, "*") {
+		return wildcardMatch(pattern, pkg)
+	}
+	return pattern == pkg
+}
+
+// matches s against a pattern where '*' stands for any sequence
+// of characters, slashes included.
+func wildcardMatch(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+
+	last := len(parts) - 1
+	for _, part := range parts[1:last] {
+		idx := strings.Index(s, part)
+		if idx < 0 {
+			return false
+		}
+		s = s[idx+len(part):]
+	}
+
+	return strings.HasSuffix(s, parts[last])
+}
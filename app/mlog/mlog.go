@@ -22,6 +22,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -41,7 +42,8 @@ const (
 	tagERROR string = "[ERR] "
 	tagFATAL string = "[DIE] "
 
-	// environment variable that overrides the default (Error) Log Level for CaesarX
+	// environment variable that overrides the default (Error) Log Level for CaesarX.
+	// It may include per-package overrides: "error,github.com/acme/app/crypto=trace"
 	LOG_LEVEL_ENV string = "LOG_LEVEL_CX"
 	// environment variable that indicates the log output filename for CaesarX (default stderr)
 	LOG_FILE_ENV string = "LOG_FILE_CX"
@@ -55,12 +57,18 @@ const (
 	LevelFatal
 )
 
+const (
+	// first line of the log and catheter files, after the BOM
+	fileLeader string = "[BEG]\t> > > >   T h e   B e g i n n i n g   < < < <\n"
+	// last line of the log and catheter files
+	fileTrailer string = "[END]\t> > > >   T h e   E n d   < < < <\n"
+)
+
 var (
-	logMutex    sync.Mutex
-	minLogLevel LogLevel    = LevelDebug
-	ilogger     *log.Logger = nil
-	logFile     *os.File    = nil
-	catFile     *os.File    = nil
+	// the default logger used by the package-level functions
+	std *Logger = nil
+	// replaces os.Exit() in the Fatal functions, see SetExitFunc()
+	exitFunc atomic.Pointer[func(int)]
 	// UTF8 BOM (Byte Order Mark)
 	UTF8_BOM []byte = []byte{0xEF, 0xBB, 0xBF}
 )
@@ -70,28 +78,17 @@ var (
  *-----------------------------------------------------------------*/
 
 func init() {
-	levelString := os.Getenv(LOG_LEVEL_ENV)
-	if levelString != "" {
-		minLogLevel = parseLevel(levelString)
-	} else {
-		minLogLevel = defaultLevel
+	std = NewLogger(nil, defaultPrefix, defaultLevel)
+	// a malformed setting keeps its default and is reported once, the
+	// others still apply. The release build ignores the catheters.
+	cfg := legacyEnvConfig()
+	logName := cfg.File
+	cfg.File = ""
+	if err := std.Configure(cfg); err != nil {
+		fmt.Fprintln(os.Stderr, "mlog: ignoring environment settings:", strings.TrimPrefix(err.Error(), "mlog: "))
 	}
-
-	const CUSTOM_TIME_FORMAT = "2006-01-02 15:04:05"
-	cw := newCustomLogWriter(os.Stderr, CUSTOM_TIME_FORMAT)
-
-	//ilogger = log.New(os.Stderr, defaultPrefix, log.Ldate|log.Ltime|log.Lshortfile)
-	ilogger = log.New(os.Stderr, defaultPrefix, log.Ldate|log.Ltime|log.Lmsgprefix)
-	ilogger.SetFlags(log.Lmsgprefix)
-	outputLogFilename := os.Getenv(LOG_FILE_ENV)
-	if len(outputLogFilename) != 0 {
-		if fd, err := openLogFile(outputLogFilename, true, false); err != nil {
-			ilogger.SetOutput(cw) // fallback to stderr
-		} else {
-			ilogger.SetOutput(fd)
-		}
-	} else {
-		ilogger.SetOutput(cw)
+	if logName != "" {
+		std.setLazyLogFile(logName)
 	}
 }
 
@@ -115,6 +112,17 @@ type customLogWriter struct {
 	format string
 }
 
+// a log file opened on the first write, so that programs which never
+// log (i.e. tools reading the log file) leave it alone. If it cannot
+// be opened the entries go to stderr.
+type lazyLogFile struct {
+	mu       sync.Mutex
+	name     string
+	rotation RotationOptions
+	file     io.WriteCloser
+	opened   bool // tried already
+}
+
 /* ----------------------------------------------------------------
  *							C o n s t r u c t o r s
  *-----------------------------------------------------------------*/
@@ -128,64 +136,107 @@ func newCustomLogWriter(w io.Writer, timeStampFormat string) *customLogWriter {
  *-----------------------------------------------------------------*/
 
 func (clw *customLogWriter) Write(p []byte) (n int, err error) {
-	timestamp := time.Now().Format(clw.format)
+	return clw.writeAt(time.Now(), p)
+}
+
+// writes p stamped with the given time rather than the current one
+func (clw *customLogWriter) writeAt(t time.Time, p []byte) (n int, err error) {
+	timestamp := t.Format(clw.format)
 	formattedMessage := fmt.Sprintf("%s %s", timestamp, p)
 	return clw.writer.Write([]byte(formattedMessage))
 }
 
+// implements io.Writer
+func (f *lazyLogFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.opened {
+		f.opened = true
+		var err error
+		if f.file, err = openLogFile(f.name, false, f.rotation); err != nil {
+			fmt.Fprintf(os.Stderr, "mlog: %v, logging to stderr\n", err)
+		}
+	}
+	if f.file == nil {
+		return os.Stderr.Write(p)
+	}
+	return f.file.Write(p)
+}
+
+// implements io.Closer. A file never written to is not created.
+func (f *lazyLogFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.opened = true
+	if f.file == nil {
+		return nil
+	}
+	err := closeLogFile(f.file)
+	f.file = nil
+	return err
+}
+
 /* ----------------------------------------------------------------
  *							F u n c t i o n s
  *-----------------------------------------------------------------*/
 
+// ends a log file opened by mlog with the trailer and closes it
+func closeLogFile(f io.WriteCloser) error {
+	switch file := f.(type) {
+	case *lazyLogFile:
+		// writes the trailer itself, if it was opened at all
+	case *RotatingWriter:
+		// straight to the file, a rotation would leave a new file
+		// with nothing but the leader and the trailer
+		file.writeTrailer()
+	default:
+		io.WriteString(f, fileTrailer)
+	}
+	return f.Close()
+}
+
 // opens the log file and outputs the first message to delimit
-// multiple application runs.
-func openLogFile(filePath string, isMainLog, truncate bool) (*os.File, error) {
+// multiple application runs. The file is rotated if the rotation
+// options call for it.
+func openLogFile(filePath string, truncate bool, rotation RotationOptions) (io.WriteCloser, error) {
+	if rotation.Enabled() {
+		rw, err := NewRotatingWriter(filePath, rotation, truncate)
+		if err != nil {
+			return nil, err
+		}
+		// straight to the file, a rotation would write it twice
+		rw.writeLeader()
+		return rw, nil
+	}
+
 	fileFlags := os.O_CREATE | os.O_WRONLY
 	if truncate {
 		fileFlags |= os.O_TRUNC
 	} else {
 		fileFlags |= os.O_APPEND
 	}
-
 	logFileX, err := os.OpenFile(filePath, fileFlags, 0666)
 	if err != nil {
 		return nil, err
 	}
 
-	const LEADER string = "[BEG]\t> > > >   T h e   B e g i n n i n g   < < < <\n"
-	if isMainLog {
-		ilogger.Print(string(UTF8_BOM), LEADER)
-	} else {
-		logFileX.WriteString(string(UTF8_BOM) + LEADER)
-	}
-
+	io.WriteString(logFileX, string(UTF8_BOM)+fileLeader)
 	return logFileX, nil
 }
 
 // CloseLogFiles to close the log file. Call this in a defer statement in your
-// main() IF you specified a log filename in the LOG_FILENAME environment var.
-// It does nothing if you used SetOutput() with your own file writer.
+// main() IF you specified a log filename in the LOG_FILENAME environment var
+// or enabled the asynchronous pipeline, whose queue is drained first.
+// It does nothing else if you used SetOutput() with your own file writer.
 func CloseLogFiles() {
-	const TRAILER string = "[END]\t> > > >   T h e   E n d   < < < <\n"
-	if logFile != nil {
-		ilogger.Print(TRAILER)
-		err := logFile.Close()
-		if err != nil {
-			ilogger.Printf("Error closing log file: %v", err)
-		}
-	}
-
-	if catFile != nil {
-		catFile.WriteString(TRAILER)
-		err := catFile.Close()
-		if err != nil {
-			ilogger.Printf("Error closing catheter file: %v", err)
-		}
-	}
+	std.Close()
 }
 
-// parse a string to convert it to a LogLevel value
-func parseLevel(s string) LogLevel {
+// ParseLevel returns the level named s (trace, debug, info, warn or
+// warning, error or fatal), ignoring case and surrounding blanks.
+func ParseLevel(s string) (LogLevel, error) {
 	var lvl LogLevel
 	s = strings.Trim(s, " \t")
 
@@ -211,38 +262,123 @@ func parseLevel(s string) LogLevel {
 		lvl = LevelFatal
 
 	default:
-		lvl = LevelFatal
+		return LevelFatal, fmt.Errorf("mlog: unknown level %q", s)
+	}
+
+	return lvl, nil
+}
+
+// the short level tag that prefixes every log entry
+func levelTag(level LogLevel) string {
+	switch level {
+	case LevelTrace:
+		return tagTRACE
+	case LevelDebug:
+		return tagDEBUG
+	case LevelInfo:
+		return tagINFO
+	case LevelWarning:
+		return tagWARN
+	case LevelError:
+		return tagERROR
+	default:
+		return tagFATAL
 	}
+}
 
-	return lvl
+// the name of the level as accepted by LOG_LEVEL_CX
+func levelName(level LogLevel) string {
+	switch level {
+	case LevelTrace:
+		return "trace"
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarning:
+		return "warning"
+	case LevelError:
+		return "error"
+	default:
+		return "fatal"
+	}
+}
+
+// SetExitFunc replaces os.Exit() in the Fatal functions, i.e. so that
+// tests survive them, and returns the previous replacement (nil for
+// os.Exit). A nil fn restores os.Exit(). The Fatal functions return if
+// fn does.
+func SetExitFunc(fn func(code int)) func(code int) {
+	var previous *func(int)
+	if fn == nil {
+		previous = exitFunc.Swap(nil)
+	} else {
+		previous = exitFunc.Swap(&fn)
+	}
+	if previous == nil {
+		return nil
+	}
+	return *previous
+}
+
+// terminates the application, unless SetExitFunc() says otherwise
+func exit(code int) {
+	if fn := exitFunc.Load(); fn != nil {
+		(*fn)(code)
+		return
+	}
+	os.Exit(code)
+}
+
+// Default returns the default logger used by the package-level functions.
+func Default() *Logger {
+	return std
+}
+
+// Enabled reports whether an entry at the given level from the calling
+// package would be written by the default logger. Guard expensive log
+// preparations with it:
+//
+//	if mlog.Enabled(mlog.LevelDebug) {
+//		mlog.DebugT("state", mlog.String("Dump", expensiveDump()))
+//	}
+func Enabled(level LogLevel) bool {
+	return std.isEnabled(level)
 }
 
 // SetLevel sets the current logging level. Unlike log and slog
 // the mlog package supports logging levels.
 func SetLevel(newLevel LogLevel) LogLevel {
-	logMutex.Lock()
-	defer logMutex.Unlock()
-
-	oldLevel := minLogLevel
-	minLogLevel = newLevel
-	return oldLevel
+	return std.SetLevel(newLevel)
 }
 
 // SetPrefix sets the prefix to appear on all log entries
 func SetPrefix(prefix string) {
-	logMutex.Lock()
-	defer logMutex.Unlock()
-
-	ilogger.SetPrefix(prefix)
+	std.SetPrefix(prefix)
 }
 
 // SetOutput sets the logging output writer instance. By
 // default mlog uses stderr.
 func SetOutput(w io.Writer) {
-	logMutex.Lock()
-	defer logMutex.Unlock()
+	std.SetOutput(w)
+}
+
+// SetLogFile makes the named file (append mode) the output of the
+// default logger instead of the one given in LOG_FILE_CX.
+func SetLogFile(filename string) bool {
+	return std.SetLogFile(filename)
+}
 
-	ilogger.SetOutput(w)
+// SetRotation sets the rotation of the log and catheter files
+// subsequently opened by the default logger.
+func SetRotation(opts RotationOptions) {
+	std.SetRotation(opts)
+}
+
+// With returns a child of the default logger which adds the given
+// tags to every log entry.
+func With(tags ...ILogKeyValuePair) *Logger {
+	return std.With(tags...)
 }
 
 /* - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
@@ -251,100 +387,59 @@ func SetOutput(w io.Writer) {
 
 // Warning level with variadic parameters
 func Warn(v ...any) {
-	if minLogLevel <= LevelWarning {
-		v1 := append([]any{tagWARN}, v...)
-		ilogger.Print(v1...)
-	}
+	std.logs(LevelWarning, v)
 }
 
 // Warning level with format string
 func Warnf(format string, v ...any) {
-	if minLogLevel <= LevelWarning {
-		ilogger.Printf(tagWARN+format, v...)
-	}
+	std.logf(LevelWarning, format, v)
 }
 
 // Warning level with message and variadic MLog tags.
 func WarnT(message string, v ...ILogKeyValuePair) {
-	if minLogLevel <= LevelWarning {
-		var sb strings.Builder
-		sb.WriteString(tagWARN)
-		sb.WriteString(message)
-		for _, t := range v {
-			sb.WriteString(" " + t.String())
-		}
-		ilogger.Print(sb.String())
-	}
+	std.logt(LevelWarning, message, v)
 }
 
 // Error level with variadic parameters
 func Error(v ...any) {
-	if minLogLevel <= LevelError {
-		v1 := append([]any{tagERROR}, v...)
-		ilogger.Print(v1...)
-	}
+	std.logs(LevelError, v)
 }
 
 // Error level with format string
 func Errorf(format string, v ...any) {
-	if minLogLevel <= LevelError {
-		ilogger.Printf(tagERROR+format, v...)
-	}
+	std.logf(LevelError, format, v)
 }
 
 // Error level with message and variadic MLog tags.
 func ErrorT(message string, v ...ILogKeyValuePair) {
-	if minLogLevel <= LevelError {
-		var sb strings.Builder
-		sb.WriteString(tagERROR)
-		sb.WriteString(message)
-		for _, t := range v {
-			sb.WriteString(" " + t.String())
-		}
-		ilogger.Print(sb.String())
-	}
+	std.logt(LevelError, message, v)
 }
 
 // Error level limited to the error itself
 func ErrorE(err error) {
-	if minLogLevel <= LevelError {
-		ilogger.Println(tagERROR, err.Error())
-	}
+	std.logt(LevelError, err.Error(), nil)
 }
 
 // Fatal level with variadic parameters and exitCode
 // for terminating the application.
 func Fatal(exitCode int, v ...any) {
-	if minLogLevel <= LevelFatal {
-		v1 := append([]any{tagFATAL}, v...)
-		ilogger.Print(v1...)
-	}
-
-	os.Exit(exitCode)
+	std.logs(LevelFatal, v)
+	std.Flush()
+	exit(exitCode)
 }
 
 // Trace level with format string and exitCode for terminating
 // the application.
 func Fatalf(exitCode int, format string, v ...any) {
-	if minLogLevel <= LevelFatal {
-		ilogger.Printf(tagFATAL+format, v...)
-	}
-
-	os.Exit(exitCode)
+	std.logf(LevelFatal, format, v)
+	std.Flush()
+	exit(exitCode)
 }
 
 // Fatal level with message and variadic MLog tags.
 // it terminates execution with exitCode.
 func FatalT(exitCode int, message string, v ...ILogKeyValuePair) {
-	if minLogLevel <= LevelFatal {
-		var sb strings.Builder
-		sb.WriteString(tagFATAL)
-		sb.WriteString(message)
-		for _, t := range v {
-			sb.WriteString(" " + t.String())
-		}
-		ilogger.Print(sb.String())
-	}
-
-	os.Exit(exitCode)
+	std.logt(LevelFatal, message, v)
+	std.Flush()
+	exit(exitCode)
 }
@@ -10,123 +10,186 @@
  *-----------------------------------------------------------------*/
 package mlog
 
-import "strings"
+import (
+	"context"
+	"io"
+)
 
 /* ----------------------------------------------------------------
- *							F u n c t i o n s
+ *							G l o b a l s
+ *-----------------------------------------------------------------*/
+
+// the lowest level this build can log (all of them)
+const buildLevel LogLevel = LevelTrace
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
  *-----------------------------------------------------------------*/
 
 // Create a "catheter" log file. It is a supplementary lifeline for
 // exceptional logging and contains no format. Use PrintCathether()
 // for writing output.
-func SetCatheterFile(filename string) bool {
+func (l *Logger) SetCatheterFile(filename string) bool {
 	var err error = nil
-	if catFile != nil {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.catFile != nil {
 		return false
 	}
-	catFile, err = openLogFile(filename, false, true)
+	l.catFile, err = openLogFile(filename, true, l.rotation)
 
 	return err == nil
 }
 
 // Print to the catheter file.
-func PrintCatheter(message string, v ...ILogKeyValuePair) {
-	if catFile != nil {
-		var sb strings.Builder
-		sb.WriteString(tagCATHE)
-		sb.WriteString(message)
-		for _, t := range v {
-			sb.WriteString(" " + t.String())
-		}
-
-		catFile.WriteString(sb.String() + "\n")
+func (l *Logger) PrintCatheter(message string, v ...ILogKeyValuePair) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.catFile != nil {
+		tags := append(append([]ILogKeyValuePair(nil), l.tags...), v...)
+		line, _, _, _ := formatEntry(tagCATHE, message, tags)
+		io.WriteString(l.catFile, line+"\n")
 	}
 }
 
+// Trace level with variadic parameters
+func (l *Logger) Trace(v ...any) {
+	l.logs(LevelTrace, v)
+}
+
+// Trace level with format string
+func (l *Logger) Tracef(format string, v ...any) {
+	l.logf(LevelTrace, format, v)
+}
+
+// Trace level with message and variadic MLog tags.
+func (l *Logger) TraceT(message string, v ...ILogKeyValuePair) {
+	l.logt(LevelTrace, message, v)
+}
+
+// Trace level with message, context tags and variadic MLog tags.
+func (l *Logger) TraceCtx(ctx context.Context, message string, v ...ILogKeyValuePair) {
+	l.logt(LevelTrace, message, withContextTags(ctx, v))
+}
+
+// Debug level with variadic parameters
+func (l *Logger) Debug(v ...any) {
+	l.logs(LevelDebug, v)
+}
+
+// Debug level with format string
+func (l *Logger) Debugf(format string, v ...any) {
+	l.logf(LevelDebug, format, v)
+}
+
+// Debug level with message and variadic MLog tags.
+func (l *Logger) DebugT(message string, v ...ILogKeyValuePair) {
+	l.logt(LevelDebug, message, v)
+}
+
+// Debug level with message, context tags and variadic MLog tags.
+func (l *Logger) DebugCtx(ctx context.Context, message string, v ...ILogKeyValuePair) {
+	l.logt(LevelDebug, message, withContextTags(ctx, v))
+}
+
+// Information level with variadic parameters
+func (l *Logger) Info(v ...any) {
+	l.logs(LevelInfo, v)
+}
+
+// Information level with format string
+func (l *Logger) Infof(format string, v ...any) {
+	l.logf(LevelInfo, format, v)
+}
+
+// Information level with message and variadic MLog tags.
+func (l *Logger) InfoT(message string, v ...ILogKeyValuePair) {
+	l.logt(LevelInfo, message, v)
+}
+
+// Information level with message, context tags and variadic MLog tags.
+func (l *Logger) InfoCtx(ctx context.Context, message string, v ...ILogKeyValuePair) {
+	l.logt(LevelInfo, message, withContextTags(ctx, v))
+}
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// Create a "catheter" log file. It is a supplementary lifeline for
+// exceptional logging and contains no format. Use PrintCathether()
+// for writing output.
+func SetCatheterFile(filename string) bool {
+	return std.SetCatheterFile(filename)
+}
+
+// Print to the catheter file.
+func PrintCatheter(message string, v ...ILogKeyValuePair) {
+	std.PrintCatheter(message, v...)
+}
+
 /* - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
  *				P r i v i l e g e d   L e v e l s
  *- - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -*/
 
 // Trace level with variadic parameters
 func Trace(v ...any) {
-	if minLogLevel <= LevelTrace {
-		v1 := append([]any{tagTRACE}, v...)
-		ilogger.Print(v1...)
-	}
+	std.logs(LevelTrace, v)
 }
 
 // Trace level with format string
 func Tracef(format string, v ...any) {
-	if minLogLevel <= LevelTrace {
-		ilogger.Printf(tagTRACE+format, v...)
-	}
+	std.logf(LevelTrace, format, v)
 }
 
 // Trace level with message and variadic MLog tags.
 func TraceT(message string, v ...ILogKeyValuePair) {
-	if minLogLevel <= LevelTrace {
-		var sb strings.Builder
-		sb.WriteString(tagTRACE)
-		sb.WriteString(message)
-		for _, t := range v {
-			sb.WriteString(" " + t.String())
-		}
-		ilogger.Print(sb.String())
-	}
+	std.logt(LevelTrace, message, v)
+}
+
+// Trace level with message, context tags and variadic MLog tags.
+func TraceCtx(ctx context.Context, message string, v ...ILogKeyValuePair) {
+	std.logt(LevelTrace, message, withContextTags(ctx, v))
 }
 
 // Debug level with variadic parameters
 func Debug(v ...any) {
-	if minLogLevel <= LevelDebug {
-		v1 := append([]any{tagDEBUG}, v...)
-		ilogger.Print(v1...)
-	}
+	std.logs(LevelDebug, v)
 }
 
 // Debug level with format string
 func Debugf(format string, v ...any) {
-	if minLogLevel <= LevelDebug {
-		ilogger.Printf(tagDEBUG+format, v...)
-	}
+	std.logf(LevelDebug, format, v)
 }
 
 // Debug level with message and variadic MLog tags.
 func DebugT(message string, v ...ILogKeyValuePair) {
-	if minLogLevel <= LevelDebug {
-		var sb strings.Builder
-		sb.WriteString(tagDEBUG)
-		sb.WriteString(message)
-		for _, t := range v {
-			sb.WriteString(" " + t.String())
-		}
-		ilogger.Print(sb.String())
-	}
+	std.logt(LevelDebug, message, v)
+}
+
+// Debug level with message, context tags and variadic MLog tags.
+func DebugCtx(ctx context.Context, message string, v ...ILogKeyValuePair) {
+	std.logt(LevelDebug, message, withContextTags(ctx, v))
 }
 
 // Information level with variadic parameters
 func Info(v ...any) {
-	if minLogLevel <= LevelInfo {
-		v1 := append([]any{tagINFO}, v...)
-		ilogger.Print(v1...)
-	}
+	std.logs(LevelInfo, v)
 }
 
 // Information level with format string
 func Infof(format string, v ...any) {
-	if minLogLevel <= LevelInfo {
-		ilogger.Printf(tagINFO+format, v...)
-	}
+	std.logf(LevelInfo, format, v)
 }
 
 // Information level with message and variadic MLog tags.
 func InfoT(message string, v ...ILogKeyValuePair) {
-	if minLogLevel <= LevelInfo {
-		var sb strings.Builder
-		sb.WriteString(tagINFO)
-		sb.WriteString(message)
-		for _, t := range v {
-			sb.WriteString(" " + t.String())
-		}
-		ilogger.Print(sb.String())
-	}
+	std.logt(LevelInfo, message, v)
+}
+
+// Information level with message, context tags and variadic MLog tags.
+func InfoCtx(ctx context.Context, message string, v ...ILogKeyValuePair) {
+	std.logt(LevelInfo, message, withContextTags(ctx, v))
 }
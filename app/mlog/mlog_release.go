@@ -11,6 +11,63 @@
  *-----------------------------------------------------------------*/
 package mlog
 
+import "context"
+
+/* ----------------------------------------------------------------
+ *							G l o b a l s
+ *-----------------------------------------------------------------*/
+
+// the lowest level this build can log (Trace, Debug & Info are stripped off)
+const buildLevel LogLevel = LevelWarning
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// The "catheter" feature is not enabled.
+func (l *Logger) SetCatheterFile(filename string) bool {
+	return false
+}
+
+// The "catheter" feature is not enabled.
+func (l *Logger) PrintCatheter(message string, v ...ILogKeyValuePair) {}
+
+// Trace level with variadic parameters
+func (l *Logger) Trace(v ...any) {}
+
+// Trace level with format string
+func (l *Logger) Tracef(format string, v ...any) {}
+
+// Trace level with message and variadic MLog tags.
+func (l *Logger) TraceT(message string, v ...ILogKeyValuePair) {}
+
+// Trace level with message, context tags and variadic MLog tags.
+func (l *Logger) TraceCtx(ctx context.Context, message string, v ...ILogKeyValuePair) {}
+
+// Debug level with variadic parameters
+func (l *Logger) Debug(v ...any) {}
+
+// Debug level with format string
+func (l *Logger) Debugf(format string, v ...any) {}
+
+// Debug level with message and variadic MLog tags.
+func (l *Logger) DebugT(message string, v ...ILogKeyValuePair) {}
+
+// Debug level with message, context tags and variadic MLog tags.
+func (l *Logger) DebugCtx(ctx context.Context, message string, v ...ILogKeyValuePair) {}
+
+// Information level with variadic parameters
+func (l *Logger) Info(v ...any) {}
+
+// Information level with format string
+func (l *Logger) Infof(format string, v ...any) {}
+
+// Information level with message and variadic MLog tags.
+func (l *Logger) InfoT(message string, v ...ILogKeyValuePair) {}
+
+// Information level with message, context tags and variadic MLog tags.
+func (l *Logger) InfoCtx(ctx context.Context, message string, v ...ILogKeyValuePair) {}
+
 /* ----------------------------------------------------------------
  *							F u n c t i o n s
  *-----------------------------------------------------------------*/
@@ -36,6 +93,9 @@ func Tracef(format string, v ...any) {}
 // Trace level with message and variadic MLog tags.
 func TraceT(message string, v ...ILogKeyValuePair) {}
 
+// Trace level with message, context tags and variadic MLog tags.
+func TraceCtx(ctx context.Context, message string, v ...ILogKeyValuePair) {}
+
 // Debug level with variadic parameters
 func Debug(v ...any) {}
 
@@ -45,6 +105,9 @@ func Debugf(format string, v ...any) {}
 // Debug level with message and variadic MLog tags.
 func DebugT(message string, v ...ILogKeyValuePair) {}
 
+// Debug level with message, context tags and variadic MLog tags.
+func DebugCtx(ctx context.Context, message string, v ...ILogKeyValuePair) {}
+
 // Information level with variadic parameters
 func Info(v ...any) {}
 
@@ -53,3 +116,6 @@ func Infof(format string, v ...any) {}
 
 // Information level with message and variadic MLog tags.
 func InfoT(message string, v ...ILogKeyValuePair) {}
+
+// Information level with message, context tags and variadic MLog tags.
+func InfoCtx(ctx context.Context, message string, v ...ILogKeyValuePair) {}
@@ -0,0 +1,281 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * HTTP admin handler to mount on a local debug mux. A GET shows the
+ * level, per-package overrides, sinks, per-level counters and LogGate
+ * filters as JSON; a PUT changes the level, overrides or filters. The
+ * gate is reached through FilterGate so that the handler builds where
+ * app/logx does not (Windows).
+ *-----------------------------------------------------------------*/
+package mlogadmin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/lordofscripts/goapp/app/mlog"
+)
+
+/* ----------------------------------------------------------------
+ *							G l o b a l s
+ *-----------------------------------------------------------------*/
+
+// the largest PUT body accepted
+const maxAdminBody int64 = 64 * 1024
+
+var _ http.Handler = (*Handler)(nil)
+
+/* ----------------------------------------------------------------
+ *							I n t e r f a c e s
+ *-----------------------------------------------------------------*/
+
+// FilterGate is what the handler uses of a logx.LogGate, whose filters
+// are of type F (logx.LogFilter).
+type FilterGate[F any] interface {
+	GetFilters() map[string]F
+	SetFilters(filters map[string]F)
+}
+
+// the filters of a gate, whatever their type
+type filterAccess interface {
+	get() any
+	decode(data json.RawMessage) (any, int, error)
+	set(filters any)
+}
+
+/* ----------------------------------------------------------------
+ *							T y p e s
+ *-----------------------------------------------------------------*/
+
+// Handler inspects and changes the configuration of a logger (and
+// optionally of a LogGate) over HTTP. Mount it on a local debug mux
+// only, it has no authentication of its own.
+type Handler struct {
+	l       *mlog.Logger
+	filters filterAccess // nil without a gate
+}
+
+// the filterAccess of a FilterGate
+type gateFilters[F any] struct {
+	gate FilterGate[F]
+}
+
+// the GET response and the PUT request. In a PUT every field is
+// optional and only those present are changed.
+type adminStatus struct {
+	Level     *string           `json:"level,omitempty"`
+	Overrides *[]adminOverride  `json:"overrides,omitempty"`
+	Sinks     []adminSink       `json:"sinks,omitempty"`
+	Counters  map[string]uint64 `json:"counters,omitempty"`
+	Filters   *json.RawMessage  `json:"filters,omitempty"`
+}
+
+type adminOverride struct {
+	Package string `json:"package"`
+	Level   string `json:"level"`
+}
+
+type adminSink struct {
+	Writer string `json:"writer"`
+	Level  string `json:"level"`
+}
+
+/* ----------------------------------------------------------------
+ *							C o n s t r u c t o r s
+ *-----------------------------------------------------------------*/
+
+// NewHandler creates the admin handler of the logger, or of the
+// default logger if nil.
+//
+//	mux.Handle("/debug/mlog", mlogadmin.NewHandler(nil))
+//
+// Then, for example:
+//
+//	curl -X PUT -d '{"level":"debug"}' http://localhost:6060/debug/mlog
+//	curl -X PUT -d '{"overrides":[{"package":"*/crypto","level":"trace"}]}' ...
+func NewHandler(l *mlog.Logger) *Handler {
+	if l == nil {
+		l = mlog.Default()
+	}
+	return &Handler{l: l}
+}
+
+// WithFilters adds the filters of a gate to the handler and returns it.
+//
+//	gate := logx.GetLogGateInstance()
+//	handler := mlogadmin.WithFilters[logx.LogFilter](mlogadmin.NewHandler(nil), gate)
+func WithFilters[F any](h *Handler, gate FilterGate[F]) *Handler {
+	h.filters = &gateFilters[F]{gate}
+	return h
+}
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+
+	case http.MethodPut:
+		var update adminStatus
+		decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAdminBody))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&update); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err := h.apply(&update); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(h.status())
+}
+
+// the current configuration and counters
+func (h *Handler) status() *adminStatus {
+	level := levelName(h.l.Level())
+	overrides := make([]adminOverride, 0)
+	for _, o := range h.l.LevelOverrides() {
+		overrides = append(overrides, adminOverride{o.Package, levelName(o.Level)})
+	}
+
+	sinks := make([]adminSink, 0)
+	for _, s := range h.l.Sinks() {
+		sinks = append(sinks, adminSink{s.String(), levelName(s.Level())})
+	}
+
+	counters := make(map[string]uint64)
+	for level := mlog.LevelTrace; level <= mlog.LevelFatal; level++ {
+		counters[levelName(level)] = h.l.Count(level)
+	}
+
+	status := &adminStatus{Level: &level, Overrides: &overrides, Sinks: sinks, Counters: counters}
+	if h.filters != nil {
+		if data, err := json.Marshal(h.filters.get()); err == nil {
+			filters := json.RawMessage(data)
+			status.Filters = &filters
+		}
+	}
+	return status
+}
+
+// validates the whole update before changing anything
+func (h *Handler) apply(update *adminStatus) error {
+	if update.Sinks != nil || update.Counters != nil {
+		return fmt.Errorf("sinks and counters are read-only")
+	}
+	if update.Filters != nil && h.filters == nil {
+		return fmt.Errorf("there is no LogGate to filter")
+	}
+
+	var level mlog.LogLevel
+	if update.Level != nil {
+		var err error
+		if level, err = mlog.ParseLevel(*update.Level); err != nil {
+			return fmt.Errorf("unknown level %q", *update.Level)
+		}
+	}
+
+	var overrides []mlog.LevelOverride = nil
+	if update.Overrides != nil {
+		for _, override := range *update.Overrides {
+			ruleLevel, err := mlog.ParseLevel(override.Level)
+			if err != nil {
+				return fmt.Errorf("unknown level %q for package %q", override.Level, override.Package)
+			}
+			if override.Package == "" {
+				return fmt.Errorf("missing package in override")
+			}
+			overrides = append(overrides, mlog.LevelOverride{Package: override.Package, Level: ruleLevel})
+		}
+	}
+
+	var filters any = nil
+	var filterCount int
+	if update.Filters != nil {
+		var err error
+		if filters, filterCount, err = h.filters.decode(*update.Filters); err != nil {
+			return err
+		}
+	}
+
+	if update.Level != nil {
+		from := h.l.SetLevel(level)
+		h.notify(fmt.Sprintf("changed the level from %s to %s", levelName(from), levelName(level)))
+	}
+	if update.Overrides != nil {
+		h.l.SetLevelOverrides(overrides)
+		h.notify(fmt.Sprintf("set %d per-package overrides", len(overrides)))
+	}
+	if update.Filters != nil {
+		h.filters.set(filters)
+		h.notify(fmt.Sprintf("set %d LogGate filters", filterCount))
+	}
+	return nil
+}
+
+// implements filterAccess
+func (g *gateFilters[F]) get() any {
+	return g.gate.GetFilters()
+}
+
+// implements filterAccess: the filters of a PUT, rejecting unknown
+// fields, and how many there are
+func (g *gateFilters[F]) decode(data json.RawMessage) (any, int, error) {
+	var filters map[string]F
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&filters); err != nil {
+		return nil, 0, fmt.Errorf("invalid filters: %v", err)
+	}
+	return filters, len(filters), nil
+}
+
+// implements filterAccess with what decode() returned
+func (g *gateFilters[F]) set(filters any) {
+	g.gate.SetFilters(filters.(map[string]F))
+}
+
+// logs a configuration change regardless of the level
+func (h *Handler) notify(change string) {
+	h.l.Notice("mlog: admin handler " + change)
+}
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// the name of the level as accepted by mlog.ParseLevel()
+func levelName(level mlog.LogLevel) string {
+	switch level {
+	case mlog.LevelTrace:
+		return "trace"
+	case mlog.LevelDebug:
+		return "debug"
+	case mlog.LevelInfo:
+		return "info"
+	case mlog.LevelWarning:
+		return "warning"
+	case mlog.LevelError:
+		return "error"
+	default:
+		return "fatal"
+	}
+}
@@ -0,0 +1,178 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Tests of the admin handler through httptest: the GET status, PUT of
+ * the level, overrides and filters, and the rejection of invalid
+ * updates without changing anything.
+ *-----------------------------------------------------------------*/
+package mlogadmin
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lordofscripts/goapp/app/mlog"
+)
+
+/* ----------------------------------------------------------------
+ *							T y p e s
+ *-----------------------------------------------------------------*/
+
+type testFilter struct {
+	LogLevel string `json:"log_level"`
+}
+
+// a FilterGate like logx.LogGate
+type testGate struct {
+	filters map[string]testFilter
+}
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+func (g *testGate) GetFilters() map[string]testFilter {
+	return g.filters
+}
+
+func (g *testGate) SetFilters(filters map[string]testFilter) {
+	g.filters = filters
+}
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// sends the request and decodes the JSON response, if successful
+func serve(t *testing.T, h http.Handler, method, body string) (int, map[string]any) {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(method, "/debug/mlog", strings.NewReader(body)))
+
+	var status map[string]any
+	if w.Code == http.StatusOK {
+		if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+			t.Fatalf("%s response: %v", method, err)
+		}
+	}
+	return w.Code, status
+}
+
+func TestHandlerGet(t *testing.T) {
+	l := mlog.NewLogger(io.Discard, "", mlog.LevelWarning)
+	l.Warn("one")
+	l.Warn("two")
+	l.Error("three")
+
+	code, status := serve(t, NewHandler(l), http.MethodGet, "")
+	if code != http.StatusOK {
+		t.Fatalf("GET status %d", code)
+	}
+	if status["level"] != "warning" {
+		t.Errorf("level %v, want warning", status["level"])
+	}
+	if overrides, _ := status["overrides"].([]any); len(overrides) != 0 {
+		t.Errorf("overrides %v, want none", overrides)
+	}
+	counters, _ := status["counters"].(map[string]any)
+	if counters["warning"] != 2.0 || counters["error"] != 1.0 || counters["fatal"] != 0.0 {
+		t.Errorf("counters %v", counters)
+	}
+	if _, found := status["filters"]; found {
+		t.Error("filters without a gate")
+	}
+}
+
+func TestHandlerPutLevel(t *testing.T) {
+	l := mlog.NewLogger(io.Discard, "", mlog.LevelWarning)
+
+	code, status := serve(t, NewHandler(l), http.MethodPut, `{"level":"error"}`)
+	if code != http.StatusOK {
+		t.Fatalf("PUT status %d", code)
+	}
+	if l.Level() != mlog.LevelError || status["level"] != "error" {
+		t.Errorf("level %v (%v), want error", l.Level(), status["level"])
+	}
+}
+
+func TestHandlerPutOverrides(t *testing.T) {
+	l := mlog.NewLogger(io.Discard, "", mlog.LevelWarning)
+	h := NewHandler(l)
+
+	body := `{"overrides":[{"package":"*/crypto","level":"trace"},{"package":"github.com/acme/app","level":"error"}]}`
+	if code, _ := serve(t, h, http.MethodPut, body); code != http.StatusOK {
+		t.Fatalf("PUT status %d", code)
+	}
+	want := []mlog.LevelOverride{{Package: "*/crypto", Level: mlog.LevelTrace}, {Package: "github.com/acme/app", Level: mlog.LevelError}}
+	if got := l.LevelOverrides(); !reflect.DeepEqual(got, want) {
+		t.Errorf("overrides %v, want %v", got, want)
+	}
+
+	if code, _ := serve(t, h, http.MethodPut, `{"overrides":[]}`); code != http.StatusOK {
+		t.Fatalf("PUT status %d", code)
+	}
+	if got := l.LevelOverrides(); len(got) != 0 {
+		t.Errorf("overrides %v, want none", got)
+	}
+}
+
+func TestHandlerRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		code   int
+	}{
+		{"unknown level", http.MethodPut, `{"level":"loud"}`, http.StatusBadRequest},
+		{"unknown override level", http.MethodPut, `{"level":"error","overrides":[{"package":"a","level":"loud"}]}`, http.StatusBadRequest},
+		{"missing package", http.MethodPut, `{"level":"error","overrides":[{"level":"info"}]}`, http.StatusBadRequest},
+		{"unknown field", http.MethodPut, `{"level":"error","verbose":true}`, http.StatusBadRequest},
+		{"not JSON", http.MethodPut, `level=error`, http.StatusBadRequest},
+		{"counters", http.MethodPut, `{"level":"error","counters":{"info":0}}`, http.StatusBadRequest},
+		{"filters without gate", http.MethodPut, `{"level":"error","filters":{}}`, http.StatusBadRequest},
+		{"method", http.MethodDelete, ``, http.StatusMethodNotAllowed},
+	}
+
+	for _, test := range tests {
+		l := mlog.NewLogger(io.Discard, "", mlog.LevelWarning)
+		l.SetLevelOverrides([]mlog.LevelOverride{{Package: "keep", Level: mlog.LevelInfo}})
+
+		if code, _ := serve(t, NewHandler(l), test.method, test.body); code != test.code {
+			t.Errorf("%s: status %d, want %d", test.name, code, test.code)
+		}
+		if l.Level() != mlog.LevelWarning || len(l.LevelOverrides()) != 1 {
+			t.Errorf("%s: changed the configuration", test.name)
+		}
+	}
+}
+
+func TestHandlerFilters(t *testing.T) {
+	l := mlog.NewLogger(io.Discard, "", mlog.LevelWarning)
+	gate := &testGate{filters: map[string]testFilter{"app": {"INFO"}}}
+	h := WithFilters[testFilter](NewHandler(l), gate)
+
+	_, status := serve(t, h, http.MethodGet, "")
+	if filters, _ := status["filters"].(map[string]any); !reflect.DeepEqual(filters, map[string]any{"app": map[string]any{"log_level": "INFO"}}) {
+		t.Errorf("filters %v", status["filters"])
+	}
+
+	if code, _ := serve(t, h, http.MethodPut, `{"filters":{"db":{"log_level":"DEBUG"}}}`); code != http.StatusOK {
+		t.Fatalf("PUT status %d", code)
+	}
+	if want := map[string]testFilter{"db": {"DEBUG"}}; !reflect.DeepEqual(gate.filters, want) {
+		t.Errorf("filters %v, want %v", gate.filters, want)
+	}
+
+	if code, _ := serve(t, h, http.MethodPut, `{"level":"error","filters":{"db":{"level":"DEBUG"}}}`); code != http.StatusBadRequest {
+		t.Errorf("unknown filter field: status %d", code)
+	}
+	if l.Level() != mlog.LevelWarning || gate.filters["db"].LogLevel != "DEBUG" {
+		t.Error("a rejected update changed the configuration")
+	}
+}
@@ -0,0 +1,513 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * MLog Logger instances. Each Logger has its own level, prefix,
+ * output writer and catheter so that several libraries in the same
+ * binary can log independently. The package-level functions are
+ * thin wrappers over the default Logger.
+ *-----------------------------------------------------------------*/
+package mlog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/* ----------------------------------------------------------------
+ *							T y p e s
+ *-----------------------------------------------------------------*/
+
+// Logger is an mlog logger instance. Child loggers obtained with
+// With() share the configuration (level, prefix, output & catheter)
+// of their parent but add their own bound tags to every entry.
+type Logger struct {
+	*loggerCore
+	tags []ILogKeyValuePair
+}
+
+// a log entry on its way to the output
+type record struct {
+	time    time.Time
+	level   LogLevel
+	message string
+	tags    []ILogKeyValuePair // bound tags followed by the caller's
+	line    string             // level tag, message and tags
+	tagText []string           // each tag as rendered in the line
+	main    bool               // goes to the main output, else only to sinks
+	pc      uintptr            // of the call site, if a sink wants it
+}
+
+// the state shared by a logger and all its children
+type loggerCore struct {
+	mu        sync.Mutex // configuration changes
+	outMu     sync.Mutex // output writes
+	level     atomic.Int32
+	spec      atomic.Pointer[levelSpec]
+	async     atomic.Pointer[asyncQueue]
+	dedup     atomic.Pointer[dedupStage]
+	sinks     atomic.Pointer[[]*Sink]
+	sinkLevel atomic.Int32 // lowest level of all sinks
+	caller    atomic.Bool  // a sink wants the call site
+	clock     atomic.Pointer[func() time.Time]
+	prefix    string
+	out       io.Writer
+	theme     *Theme  // colors of the main output, nil for none
+	layout    *Layout // of the main output, nil for the default
+	logFile   io.WriteCloser
+	logName   string // of logFile, to reopen it
+	catFile   io.WriteCloser
+	catheters map[string]*CatheterChannel // named catheters
+	rotation  RotationOptions
+	recent    recentLines // for the crash report
+	counts    [LevelFatal - LevelTrace + 1]atomic.Uint64
+}
+
+/* ----------------------------------------------------------------
+ *							C o n s t r u c t o r s
+ *-----------------------------------------------------------------*/
+
+// NewLogger creates an independent logger writing to w with the given
+// prefix and minimum level. If w is nil the output goes to stderr with
+// each line prefixed by a timestamp.
+func NewLogger(w io.Writer, prefix string, level LogLevel) *Logger {
+	const CUSTOM_TIME_FORMAT = "2006-01-02 15:04:05"
+	if w == nil {
+		w = newCustomLogWriter(os.Stderr, CUSTOM_TIME_FORMAT)
+	}
+
+	core := &loggerCore{prefix: prefix, out: w, theme: themeForWriter(w)}
+	core.level.Store(int32(level))
+	core.sinkLevel.Store(int32(noSinkLevel))
+	return &Logger{loggerCore: core}
+}
+
+// a record with a plain message logged now
+func newRecord(level LogLevel, message string) *record {
+	return &record{time: time.Now(), level: level, message: message, line: levelTag(level) + message, main: true}
+}
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// Enabled reports whether an entry at the given level from the calling
+// package would be written, to the main output or any sink. Use it to
+// guard expensive work done only for logging.
+func (l *Logger) Enabled(level LogLevel) bool {
+	return l.isEnabled(level)
+}
+
+// With returns a child logger which adds the given tags (after those
+// of its parent) to every log entry.
+func (l *Logger) With(tags ...ILogKeyValuePair) *Logger {
+	bound := make([]ILogKeyValuePair, 0, len(l.tags)+len(tags))
+	bound = append(bound, l.tags...)
+	bound = append(bound, tags...)
+	return &Logger{loggerCore: l.loggerCore, tags: bound}
+}
+
+// Level returns the current minimum logging level.
+func (l *Logger) Level() LogLevel {
+	return LogLevel(l.level.Load())
+}
+
+// Count returns the number of entries logged at the given level so far.
+func (l *Logger) Count(level LogLevel) uint64 {
+	if level < LevelTrace || level > LevelFatal {
+		return 0
+	}
+	return l.counts[level-LevelTrace].Load()
+}
+
+// Notice writes a warning whatever the level, i.e. to record a change
+// made to the logger from the outside.
+func (l *Logger) Notice(message string) {
+	l.dispatch(newRecord(LevelWarning, message))
+}
+
+// SetLevel sets the minimum logging level and returns the previous one.
+func (l *Logger) SetLevel(newLevel LogLevel) LogLevel {
+	return LogLevel(l.level.Swap(int32(newLevel)))
+}
+
+// SetPrefix sets the prefix to appear on all log entries
+func (l *Logger) SetPrefix(prefix string) {
+	l.outMu.Lock()
+	defer l.outMu.Unlock()
+
+	l.prefix = prefix
+}
+
+// Output returns the current output writer.
+func (l *Logger) Output() io.Writer {
+	l.outMu.Lock()
+	defer l.outMu.Unlock()
+
+	return l.out
+}
+
+// SetClock makes the logger timestamp its entries with now() instead of
+// time.Now(), i.e. for reproducible tests. A nil now restores time.Now().
+func (l *Logger) SetClock(now func() time.Time) {
+	if now == nil {
+		l.clock.Store(nil)
+	} else {
+		l.clock.Store(&now)
+	}
+}
+
+// SetOutput sets the logging output writer instance. Output to a
+// terminal is colorized, see SetTheme().
+func (l *Logger) SetOutput(w io.Writer) {
+	l.outMu.Lock()
+	defer l.outMu.Unlock()
+
+	l.out = w
+	l.theme = themeForWriter(w)
+}
+
+// SetLogFile opens (append mode) the named log file and makes it the
+// output of this logger. It returns false if the file could not be
+// opened or the logger already has a log file.
+func (l *Logger) SetLogFile(filename string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.logFile != nil {
+		return false
+	}
+
+	fd, err := openLogFile(filename, false, l.rotation)
+	if err != nil {
+		return false
+	}
+
+	l.logFile = fd
+	l.logName = filename
+	l.SetOutput(fd)
+	return true
+}
+
+// Reopen closes the log file opened by SetLogFile() and opens it again
+// by name, i.e. after logrotate moved it away. Entries being written
+// meanwhile go to either the old or the new file.
+func (l *Logger) Reopen() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.logFile == nil {
+		return fmt.Errorf("mlog: no log file to reopen")
+	}
+
+	return l.switchLogFile(l.logName)
+}
+
+// opens the named log file in place of the current one, if any, and
+// makes it the output. Must be called with the configuration lock held.
+func (l *Logger) switchLogFile(filename string) error {
+	fd, err := openLogFile(filename, false, l.rotation)
+	if err != nil {
+		return err
+	}
+
+	l.outMu.Lock()
+	old := l.logFile
+	l.logFile, l.logName = fd, filename
+	if old == nil || l.out == io.Writer(old) {
+		l.out = fd
+		l.theme = themeForWriter(fd)
+	}
+	l.outMu.Unlock()
+
+	if old == nil {
+		return nil
+	}
+	return closeLogFile(old)
+}
+
+// makes the named log file the output, opened on the first entry
+func (l *Logger) setLazyLogFile(filename string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f := &lazyLogFile{name: filename, rotation: l.rotation}
+	l.outMu.Lock()
+	l.logFile, l.logName = f, filename
+	l.out = f
+	l.theme = nil
+	l.outMu.Unlock()
+}
+
+// SetRotation sets the rotation options applied to the log and catheter
+// files opened afterwards with SetLogFile() and SetCatheterFile().
+func (l *Logger) SetRotation(opts RotationOptions) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.rotation = opts
+}
+
+// Close writes the pending repeat summary, drains the asynchronous
+// queue (if any) and closes the log and catheter files opened by this
+// logger and the connections of its sinks. It does nothing else if you
+// used SetOutput() with your own file writer.
+func (l *Logger) Close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if d := l.dedup.Load(); d != nil {
+		d.flush()
+	}
+	if q := l.async.Swap(nil); q != nil {
+		q.stop()
+	}
+
+	if l.logFile != nil {
+		l.outMu.Lock()
+		err := closeLogFile(l.logFile)
+		l.logFile = nil
+		l.out = os.Stderr
+		l.theme = themeForWriter(os.Stderr)
+		l.outMu.Unlock()
+		if err != nil {
+			l.write(newRecord(LevelError, fmt.Sprintf("Error closing log file: %v", err)))
+		}
+	}
+
+	if l.catFile != nil {
+		err := closeLogFile(l.catFile)
+		l.catFile = nil
+		if err != nil {
+			l.write(newRecord(LevelError, fmt.Sprintf("Error closing catheter file: %v", err)))
+		}
+	}
+	if err := l.closeCatheters(); err != nil {
+		l.write(newRecord(LevelError, fmt.Sprintf("Error closing catheter file: %v", err)))
+	}
+
+	if sinks := l.sinks.Load(); sinks != nil {
+		for _, s := range *sinks {
+			if err := s.Close(); err != nil {
+				l.write(newRecord(LevelError, fmt.Sprintf("Error closing sink: %v", err)))
+			}
+		}
+	}
+}
+
+/* - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ *			N o n - P r i v i l e g e d   L e v e l s
+ *- - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -*/
+
+// Warning level with variadic parameters
+func (l *Logger) Warn(v ...any) {
+	l.logs(LevelWarning, v)
+}
+
+// Warning level with format string
+func (l *Logger) Warnf(format string, v ...any) {
+	l.logf(LevelWarning, format, v)
+}
+
+// Warning level with message and variadic MLog tags.
+func (l *Logger) WarnT(message string, v ...ILogKeyValuePair) {
+	l.logt(LevelWarning, message, v)
+}
+
+// Error level with variadic parameters
+func (l *Logger) Error(v ...any) {
+	l.logs(LevelError, v)
+}
+
+// Error level with format string
+func (l *Logger) Errorf(format string, v ...any) {
+	l.logf(LevelError, format, v)
+}
+
+// Error level with message and variadic MLog tags.
+func (l *Logger) ErrorT(message string, v ...ILogKeyValuePair) {
+	l.logt(LevelError, message, v)
+}
+
+// Error level limited to the error itself
+func (l *Logger) ErrorE(err error) {
+	l.logt(LevelError, err.Error(), nil)
+}
+
+// Fatal level with variadic parameters and exitCode
+// for terminating the application.
+func (l *Logger) Fatal(exitCode int, v ...any) {
+	l.logs(LevelFatal, v)
+	l.Flush()
+	exit(exitCode)
+}
+
+// Fatal level with format string and exitCode for terminating
+// the application.
+func (l *Logger) Fatalf(exitCode int, format string, v ...any) {
+	l.logf(LevelFatal, format, v)
+	l.Flush()
+	exit(exitCode)
+}
+
+// Fatal level with message and variadic MLog tags.
+// it terminates execution with exitCode.
+func (l *Logger) FatalT(exitCode int, message string, v ...ILogKeyValuePair) {
+	l.logt(LevelFatal, message, v)
+	l.Flush()
+	exit(exitCode)
+}
+
+/* - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ *				P r i v a t e   M e t h o d s
+ *- - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -*/
+
+// whether an entry at the given level would be logged, either to the
+// main output (first result) or to some sink. It must be called directly
+// by logs(), logf(), logt() or isEnabled() because per-package overrides are resolved
+// from the call site of the logging function.
+func (l *Logger) enabled(level LogLevel) (main bool, any bool) {
+	threshold := LogLevel(l.level.Load())
+	if spec := l.spec.Load(); spec != nil {
+		if override := spec.levelFor(framesToCallSite); override != noOverride {
+			threshold = override
+		}
+	}
+
+	main = threshold <= level
+	return main, main || LogLevel(l.sinkLevel.Load()) <= level
+}
+
+// enabled() for the public Enabled() functions, it keeps the same
+// frame distance to the call site as logs(), logf() and logt().
+func (l *Logger) isEnabled(level LogLevel) bool {
+	if level < buildLevel {
+		return false
+	}
+	_, ok := l.enabled(level)
+	return ok
+}
+
+// log the variadic parameters in free form
+func (l *Logger) logs(level LogLevel, v []any) {
+	if main, ok := l.enabled(level); ok {
+		l.output(level, main, fmt.Sprint(v...), nil)
+	}
+}
+
+// log with a format string
+func (l *Logger) logf(level LogLevel, format string, v []any) {
+	if main, ok := l.enabled(level); ok {
+		l.output(level, main, fmt.Sprintf(format, v...), nil)
+	}
+}
+
+// log a message with MLog tags
+func (l *Logger) logt(level LogLevel, message string, v []ILogKeyValuePair) {
+	if main, ok := l.enabled(level); ok {
+		l.output(level, main, message, v)
+	}
+}
+
+// builds the entry: level tag, message, bound tags and then the
+// tags given by the caller.
+func (l *Logger) output(level LogLevel, main bool, message string, v []ILogKeyValuePair) {
+	var pc uintptr
+	if l.caller.Load() {
+		// Callers, output, logX and the public function
+		var pcs [1]uintptr
+		runtime.Callers(4, pcs[:])
+		pc = pcs[0]
+	}
+	l.outputAt(l.now(), pc, level, main, message, v)
+}
+
+// builds the entry with the given timestamp and call site (0 if unknown)
+func (l *Logger) outputAt(t time.Time, pc uintptr, level LogLevel, main bool, message string, v []ILogKeyValuePair) {
+	tags := v
+	if len(l.tags) > 0 {
+		tags = make([]ILogKeyValuePair, 0, len(l.tags)+len(v))
+		tags = append(tags, l.tags...)
+		tags = append(tags, v...)
+	}
+
+	if LevelTrace <= level && level <= LevelFatal {
+		l.counts[level-LevelTrace].Add(1)
+	}
+	if q := l.async.Load(); q != nil && q.discards(level) {
+		return
+	}
+	line, message, tags, tagText := formatEntry(levelTag(level), message, tags)
+	l.dispatch(&record{time: t, level: level, message: message, tags: tags, line: line, tagText: tagText, main: main, pc: pc})
+}
+
+// the time of the logger's clock
+func (c *loggerCore) now() time.Time {
+	if clock := c.clock.Load(); clock != nil {
+		return (*clock)()
+	}
+	return time.Now()
+}
+
+// hands the record to the deduplicating stage, if any, or else
+// enqueues it.
+func (c *loggerCore) dispatch(r *record) {
+	if d := c.dedup.Load(); d != nil {
+		d.put(r)
+		return
+	}
+	c.enqueue(r)
+}
+
+// hands the record to the asynchronous queue or, if there is none,
+// writes it right away.
+func (c *loggerCore) enqueue(r *record) {
+	if q := c.async.Load(); q != nil && q.put(r) {
+		return
+	}
+	c.write(r)
+}
+
+// writes the record to the main output. Must be called with the
+// output lock held.
+func (c *loggerCore) writeMain(prefix string, r *record) {
+	if c.layout != nil {
+		// the layout has its own timestamp
+		w := c.out
+		if cw, ok := w.(*customLogWriter); ok {
+			w = cw.writer
+		}
+		w.Write([]byte(terminated(c.layout.format(prefix, r, c.theme))))
+		return
+	}
+
+	text := r.line
+	if c.theme != nil {
+		text = c.theme.entry(r.level, r.message, r.tagText)
+	}
+	line := []byte(terminated(prefix + text))
+	if cw, ok := c.out.(*customLogWriter); ok {
+		cw.writeAt(r.time, line)
+	} else {
+		c.out.Write(line)
+	}
+}
+
+// writes the record to the main output and the sinks
+func (c *loggerCore) write(r *record) {
+	c.outMu.Lock()
+	prefix := c.prefix
+	c.recent.add(r.time, prefix, r.line)
+	if r.main {
+		c.writeMain(prefix, r)
+	}
+	c.outMu.Unlock()
+
+	c.writeSinks(prefix, r)
+}
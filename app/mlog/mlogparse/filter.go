@@ -0,0 +1,66 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Entry filters by level, time range, package and tags.
+ *-----------------------------------------------------------------*/
+package mlogparse
+
+import (
+	"time"
+
+	"github.com/lordofscripts/goapp/app/mlog"
+)
+
+/* ----------------------------------------------------------------
+ *							T y p e s
+ *-----------------------------------------------------------------*/
+
+// Filter selects entries. Its zero value selects them all. Lines
+// without a level tag only pass the level filter.
+type Filter struct {
+	MinLevel mlog.LogLevel // zero for any
+	Since    time.Time     // zero for no lower bound
+	Until    time.Time     // zero for no upper bound
+	Package  string        // package of the call site, see mlog.MatchPackage()
+	Tags     []Tag         // each must be present; an empty Value matches any
+}
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// Match reports whether the entry passes the filter. Entries without
+// a timestamp never pass a time range.
+func (f *Filter) Match(e *Entry) bool {
+	if e.Kind == KindBegin || e.Kind == KindEnd {
+		return true
+	}
+	if e.Kind == KindEntry && f.MinLevel != 0 && e.Level < f.MinLevel {
+		return false
+	}
+
+	if !f.Since.IsZero() || !f.Until.IsZero() {
+		if e.Time.IsZero() ||
+			!f.Since.IsZero() && e.Time.Before(f.Since) ||
+			!f.Until.IsZero() && e.Time.After(f.Until) {
+			return false
+		}
+	}
+
+	if f.Package != "" {
+		pkg := e.Package()
+		if pkg == "" || !mlog.MatchPackage(f.Package, pkg) {
+			return false
+		}
+	}
+
+	for _, want := range f.Tags {
+		value, found := e.Get(want.Key)
+		if !found || want.Value != "" && value != want.Value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,411 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Parser of mlog output: "[timestamp ][prefix][LVL] message key=value..."
+ * lines, continuation lines (stacks) and the BOM/[BEG]/[END] markers
+ * that delimit the sessions (application runs) of a log file.
+ *-----------------------------------------------------------------*/
+package mlogparse
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lordofscripts/goapp/app/mlog"
+)
+
+/* ----------------------------------------------------------------
+ *							G l o b a l s
+ *-----------------------------------------------------------------*/
+
+const (
+	markerBegin string = "[BEG]\t"
+	markerEnd   string = "[END]\t"
+	bom         string = "\uFEFF"
+)
+
+// Kind of a parsed line
+const (
+	KindEntry Kind = iota // a log entry with a level tag
+	KindText              // a line without level tag, i.e. from the log package
+	KindBegin             // the [BEG] marker, a session starts
+	KindEnd               // the [END] marker, the session ended
+)
+
+// the level tags as written by mlog
+var levelTags = []struct {
+	tag      string
+	level    mlog.LogLevel
+	catheter bool
+}{
+	{"[TRC] ", mlog.LevelTrace, false},
+	{"[DBG] ", mlog.LevelDebug, false},
+	{"[INF] ", mlog.LevelInfo, false},
+	{"[WRN] ", mlog.LevelWarning, false},
+	{"[ERR] ", mlog.LevelError, false},
+	{"[DIE] ", mlog.LevelFatal, false},
+	{"[CAT] ", mlog.LevelTrace, true},
+}
+
+// the timestamps of the stderr output and the sinks. Anything else
+// must be a single RFC3339 token.
+var timeLayouts = []string{
+	"2006-01-02 15:04:05.000",
+	"2006-01-02 15:04:05",
+}
+
+/* ----------------------------------------------------------------
+ *							T y p e s
+ *-----------------------------------------------------------------*/
+
+type Kind int
+
+// Entry is a parsed log entry along with its continuation lines.
+type Entry struct {
+	Kind     Kind
+	Session  int // 0 before the first [BEG] marker
+	Line     int // line number of the entry in the input
+	Time     time.Time
+	TimeText string // the timestamp as written, empty if none
+	Prefix   string // the logger prefix, if any
+	Level    mlog.LogLevel
+	Catheter bool   // a [CAT] entry, Level is then Trace
+	LevelTag string // i.e. "[ERR]"
+	Message  string
+	Tags     []Tag
+	Extra    []string // continuation lines, i.e. a stack
+	Raw      string   // the first line as read, without BOM
+}
+
+// Tag is a key=value pair of an entry. Quoted values are unquoted.
+type Tag struct {
+	Key   string
+	Value string
+	Raw   string // as written, i.e. Name='John'
+}
+
+// Session is an application run in a log file.
+type Session struct {
+	Number  int
+	Begun   bool // it started with a [BEG] marker
+	Ended   bool // it finished with an [END] marker
+	Entries []*Entry
+}
+
+// Parser reads entries from mlog output.
+type Parser struct {
+	r       *bufio.Reader
+	follow  bool
+	partial string // incomplete last line when following
+	lineNr  int
+	session int
+	pending *Entry // waits for its continuation lines
+	marker  *Entry // returned after the pending entry
+}
+
+/* ----------------------------------------------------------------
+ *							C o n s t r u c t o r s
+ *-----------------------------------------------------------------*/
+
+// NewParser creates a parser reading mlog output from r.
+func NewParser(r io.Reader) *Parser {
+	return &Parser{r: bufio.NewReader(r)}
+}
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// implements fmt.Stringer for Kind
+func (k Kind) String() string {
+	switch k {
+	case KindEntry:
+		return "entry"
+	case KindText:
+		return "text"
+	case KindBegin:
+		return "begin"
+	default:
+		return "end"
+	}
+}
+
+// Get returns the value of the first tag with the given key.
+func (e *Entry) Get(key string) (string, bool) {
+	for _, t := range e.Tags {
+		if t.Key == key {
+			return t.Value, true
+		}
+	}
+	return "", false
+}
+
+// Package returns the package of the call site: that of the At tag
+// (see mlog.At()) or else of a {caller} layout field, i.e. the
+// "github.com/acme/app.main()#12" word. Empty if the entry has none.
+func (e *Entry) Package() string {
+	at, ok := e.Get("At")
+	if !ok {
+		at = callSite(e.Prefix + " " + e.Message)
+	}
+	if at == "" {
+		return ""
+	}
+
+	// package.function()#line or package.Struct{}.method()#line
+	lastSlash := strings.LastIndexByte(at, '/')
+	if dot := strings.IndexByte(at[lastSlash+1:], '.'); dot >= 0 {
+		return at[:lastSlash+1+dot]
+	}
+	return at
+}
+
+// Follow makes the parser treat the end of the input as temporary so
+// that Next() can be called again once the file has grown. An
+// incomplete last line is kept until its end arrives.
+func (p *Parser) Follow(on bool) {
+	p.follow = on
+}
+
+// Next returns the next entry or marker. At the end of the input it
+// returns io.EOF; when following, call it again later.
+func (p *Parser) Next() (*Entry, error) {
+	if marker := p.marker; marker != nil {
+		p.marker = nil
+		return marker, nil
+	}
+
+	for {
+		line, err := p.readLine()
+		if err != nil {
+			if previous := p.pending; previous != nil {
+				p.pending = nil
+				return previous, nil
+			}
+			return nil, err
+		}
+
+		if strings.HasPrefix(line, "\t") && p.pending != nil {
+			p.pending.Extra = append(p.pending.Extra, line)
+			continue
+		}
+
+		e := p.parseLine(line)
+		previous := p.pending
+		if e.Kind == KindBegin || e.Kind == KindEnd {
+			p.pending = nil
+			if previous == nil {
+				return e, nil
+			}
+			p.marker = e
+			return previous, nil
+		}
+
+		p.pending = e
+		if previous != nil {
+			return previous, nil
+		}
+	}
+}
+
+// reads a line without its end of line. When following, an incomplete
+// last line is kept for the next call.
+func (p *Parser) readLine() (string, error) {
+	line, err := p.r.ReadString('\n')
+	if err != nil && (p.follow || line == "") {
+		p.partial += line
+		return "", err
+	}
+
+	line = p.partial + line
+	p.partial = ""
+	p.lineNr++
+	return strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r"), nil
+}
+
+// parses a single line, tracking the session markers
+func (p *Parser) parseLine(line string) *Entry {
+	line = strings.TrimPrefix(line, bom)
+	e := &Entry{Kind: KindText, Session: p.session, Line: p.lineNr, Raw: line, Message: line}
+
+	switch {
+	case strings.HasPrefix(line, markerBegin):
+		p.session++
+		e.Kind = KindBegin
+		e.Session = p.session
+		return e
+
+	case strings.HasPrefix(line, markerEnd):
+		e.Kind = KindEnd
+		return e
+	}
+
+	rest := line
+	e.Time, e.TimeText, rest = parseTime(rest)
+
+	// the first level tag, the prefix (if any) goes before it
+	at := -1
+	for _, lt := range levelTags {
+		if idx := strings.Index(rest, lt.tag); idx >= 0 && (at < 0 || idx < at) {
+			at = idx
+			e.Level, e.Catheter, e.LevelTag = lt.level, lt.catheter, strings.TrimSpace(lt.tag)
+		}
+	}
+	if at < 0 {
+		e.Time, e.TimeText = time.Time{}, ""
+		return e
+	}
+
+	e.Kind = KindEntry
+	e.Prefix = rest[:at]
+	e.Message, e.Tags = splitTags(rest[at+len(e.LevelTag)+1:])
+	return e
+}
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// ReadSessions parses the whole input into its sessions. Entries
+// before the first [BEG] marker make up session 0, which is left out
+// if empty.
+func ReadSessions(r io.Reader) ([]*Session, error) {
+	sessions := []*Session{{Number: 0}}
+	p := NewParser(r)
+	for {
+		e, err := p.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return sessions, err
+		}
+
+		current := sessions[len(sessions)-1]
+		switch e.Kind {
+		case KindBegin:
+			sessions = append(sessions, &Session{Number: e.Session, Begun: true})
+		case KindEnd:
+			current.Ended = true
+		default:
+			current.Entries = append(current.Entries, e)
+		}
+	}
+
+	if len(sessions[0].Entries) == 0 {
+		sessions = sessions[1:]
+	}
+	return sessions, nil
+}
+
+// the leading timestamp, if any, and the rest of the line
+func parseTime(line string) (time.Time, string, string) {
+	if line == "" || line[0] < '0' || line[0] > '9' {
+		return time.Time{}, "", line
+	}
+
+	for _, layout := range timeLayouts {
+		n := len(layout)
+		if len(line) > n && line[n] == ' ' {
+			if t, err := time.ParseInLocation(layout, line[:n], time.Local); err == nil {
+				return t, line[:n], line[n+1:]
+			}
+		}
+	}
+
+	if token, rest, found := strings.Cut(line, " "); found {
+		if t, err := time.Parse(time.RFC3339Nano, token); err == nil {
+			return t, token, rest
+		}
+	}
+	return time.Time{}, "", line
+}
+
+// splits the text after the level tag into message and tags. A tag
+// starts at " key=" and extends to the next one; quoted values may
+// contain anything.
+func splitTags(s string) (string, []Tag) {
+	var tags []Tag = nil
+	message := s
+	start := -1 // of the current tag
+	for i := 0; i < len(s); i++ {
+		if s[i] != ' ' {
+			continue
+		}
+		eq := keyEnd(s, i+1)
+		if eq < 0 {
+			continue
+		}
+
+		if start < 0 {
+			message = s[:i]
+		} else {
+			tags = append(tags, newTag(s[start:i]))
+		}
+		start = i + 1
+		i = eq
+		if eq+1 < len(s) && s[eq+1] == '\'' {
+			if end := closingQuote(s, eq+2); end > 0 {
+				i = end
+			}
+		}
+	}
+	if start >= 0 {
+		tags = append(tags, newTag(s[start:]))
+	}
+	return message, tags
+}
+
+// the index of the '=' after a key starting at i, else -1
+func keyEnd(s string, i int) int {
+	for j := i; j < len(s); j++ {
+		c := s[j]
+		switch {
+		case c == '=' && j > i:
+			return j
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_':
+		case j > i && (c >= '0' && c <= '9' || c == '.' || c == '-'):
+		default:
+			return -1
+		}
+	}
+	return -1
+}
+
+// the index of the quote closing a value, one followed by a space or
+// the end of the text, else -1
+func closingQuote(s string, from int) int {
+	for j := from; j < len(s); j++ {
+		if s[j] == '\'' && (j+1 == len(s) || s[j+1] == ' ') {
+			return j
+		}
+	}
+	return -1
+}
+
+// the first word that looks like a call site, package.function()#line,
+// or an empty string
+func callSite(s string) string {
+	for _, word := range strings.Fields(s) {
+		if hash := strings.LastIndex(word, "()#"); hash > 0 && strings.Contains(word[:hash], ".") {
+			if _, err := strconv.Atoi(word[hash+3:]); err == nil {
+				return word
+			}
+		}
+	}
+	return ""
+}
+
+// a tag from its key=value rendering
+func newTag(raw string) Tag {
+	key, value, _ := strings.Cut(raw, "=")
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		value = value[1 : len(value)-1]
+	}
+	return Tag{Key: key, Value: value, Raw: raw}
+}
@@ -0,0 +1,183 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Tests of the parser and the filter over mlog output: session
+ * markers, BOM-prefixed lines, continuation lines, tags and packages.
+ *-----------------------------------------------------------------*/
+package mlogparse
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lordofscripts/goapp/app/mlog"
+)
+
+/* ----------------------------------------------------------------
+ *							G l o b a l s
+ *-----------------------------------------------------------------*/
+
+// two runs of an application appending to the same file
+const testLog = bom + "[BEG]\t> > > >   T h e   B e g i n n i n g   < < < <\n" +
+	"2025-01-02 15:04:05.000 [INF] started Version='1.2 beta' Port=8080\n" +
+	"2025-01-02 15:04:06.000 app: [ERR] failed Err=*errors.errorString: it's broken At=github.com/acme/app/db.Open()#42\n" +
+	"\tat github.com/acme/app/db.Open()#42\n" +
+	"\tat main.main()#10\n" +
+	"plain line from the log package\n" +
+	"[END]\t> > > >   T h e   E n d   < < < <\n" +
+	bom + "[BEG]\t> > > >   T h e   B e g i n n i n g   < < < <\n" +
+	"2025-01-02 16:00:00 [WRN] github.com/acme/app/crypto/aes.Seal()#7 weak key\n"
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// all the entries and markers of the input
+func parseAll(t *testing.T, input string) []*Entry {
+	var entries []*Entry
+	p := NewParser(strings.NewReader(input))
+	for {
+		e, err := p.Next()
+		if err == io.EOF {
+			return entries
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, e)
+	}
+}
+
+func TestParserMarkers(t *testing.T) {
+	entries := parseAll(t, testLog)
+
+	kinds := make([]Kind, len(entries))
+	sessions := make([]int, len(entries))
+	for i, e := range entries {
+		kinds[i], sessions[i] = e.Kind, e.Session
+	}
+	wantKinds := []Kind{KindBegin, KindEntry, KindEntry, KindText, KindEnd, KindBegin, KindEntry}
+	if !reflect.DeepEqual(kinds, wantKinds) {
+		t.Errorf("kinds %v, want %v", kinds, wantKinds)
+	}
+	if want := []int{1, 1, 1, 1, 1, 2, 2}; !reflect.DeepEqual(sessions, want) {
+		t.Errorf("sessions %v, want %v", sessions, want)
+	}
+}
+
+func TestParserBOM(t *testing.T) {
+	entries := parseAll(t, bom+"2025-01-02 15:04:05 [INF] no marker\n")
+	if len(entries) != 1 {
+		t.Fatalf("%d entries, want 1", len(entries))
+	}
+	e := entries[0]
+	if e.Kind != KindEntry || strings.HasPrefix(e.Raw, bom) || e.TimeText != "2025-01-02 15:04:05" || e.Message != "no marker" {
+		t.Errorf("entry %+v", e)
+	}
+}
+
+func TestParserContinuation(t *testing.T) {
+	entries := parseAll(t, testLog)
+	e := entries[2]
+	want := []string{"\tat github.com/acme/app/db.Open()#42", "\tat main.main()#10"}
+	if !reflect.DeepEqual(e.Extra, want) {
+		t.Errorf("continuation lines %q, want %q", e.Extra, want)
+	}
+	if e.Line != 3 || entries[3].Line != 6 {
+		t.Errorf("line numbers %d and %d, want 3 and 6", e.Line, entries[3].Line)
+	}
+
+	// a tab before the first entry is a line of its own
+	if entries := parseAll(t, "\torphan\n"); len(entries) != 1 || entries[0].Kind != KindText {
+		t.Errorf("orphan continuation %+v", entries)
+	}
+}
+
+func TestParserTags(t *testing.T) {
+	entries := parseAll(t, testLog)
+
+	started := entries[1]
+	if started.Level != mlog.LevelInfo || started.Message != "started" {
+		t.Errorf("entry %+v", started)
+	}
+	want := []Tag{{"Version", "1.2 beta", "Version='1.2 beta'"}, {"Port", "8080", "Port=8080"}}
+	if !reflect.DeepEqual(started.Tags, want) {
+		t.Errorf("tags %+v, want %+v", started.Tags, want)
+	}
+
+	failed := entries[2]
+	if failed.Prefix != "app: " || failed.LevelTag != "[ERR]" || failed.Message != "failed" {
+		t.Errorf("entry %+v", failed)
+	}
+	if value, _ := failed.Get("Err"); value != "*errors.errorString: it's broken" {
+		t.Errorf("Err=%q", value)
+	}
+	if value, found := failed.Get("Missing"); found {
+		t.Errorf("Missing=%q", value)
+	}
+}
+
+func TestEntryPackage(t *testing.T) {
+	entries := parseAll(t, testLog)
+	tests := []struct {
+		entry *Entry
+		want  string
+	}{
+		{entries[1], ""},
+		{entries[2], "github.com/acme/app/db"},         // At tag
+		{entries[6], "github.com/acme/app/crypto/aes"}, // {caller} field
+	}
+	for _, test := range tests {
+		if got := test.entry.Package(); got != test.want {
+			t.Errorf("line %d: package %q, want %q", test.entry.Line, got, test.want)
+		}
+	}
+}
+
+func TestFilterPackage(t *testing.T) {
+	entries := parseAll(t, testLog)
+	tests := []struct {
+		pattern string
+		want    []int // lines of the matching entries
+	}{
+		{"github.com/acme/app/*", []int{3, 9}},
+		{"*/crypto/*", []int{9}},
+		{"github.com/acme/app/db", []int{3}},
+		{"github.com/acme/app", nil},
+	}
+	for _, test := range tests {
+		f := &Filter{Package: test.pattern}
+		var got []int = nil
+		for _, e := range entries {
+			if e.Kind != KindBegin && e.Kind != KindEnd && f.Match(e) {
+				got = append(got, e.Line)
+			}
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: lines %v, want %v", test.pattern, got, test.want)
+		}
+	}
+}
+
+func TestReadSessions(t *testing.T) {
+	sessions, err := ReadSessions(strings.NewReader("2025-01-02 15:04:05 [INF] before\n" + testLog))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 3 {
+		t.Fatalf("%d sessions, want 3", len(sessions))
+	}
+	if s := sessions[0]; s.Begun || len(s.Entries) != 1 {
+		t.Errorf("session 0: %+v", s)
+	}
+	if s := sessions[1]; !s.Begun || !s.Ended || len(s.Entries) != 3 {
+		t.Errorf("session 1: %+v", s)
+	}
+	if s := sessions[2]; !s.Begun || s.Ended || len(s.Entries) != 1 {
+		t.Errorf("session 2: %+v", s)
+	}
+}
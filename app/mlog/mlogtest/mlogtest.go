@@ -0,0 +1,311 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Helpers to assert on mlog output in unit tests. A Recorder captures
+ * the entries of a logger as structured records, timestamps them with
+ * a fake clock and keeps the Fatal functions from exiting.
+ *
+ *	rec := mlogtest.NewRecorder(t, nil)
+ *	DoSomething()
+ *	rec.AssertLogged(t, mlog.LevelWarning, "retrying", "Attempt", "2")
+ *-----------------------------------------------------------------*/
+package mlogtest
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lordofscripts/goapp/app/mlog"
+)
+
+/* ----------------------------------------------------------------
+ *							G l o b a l s
+ *-----------------------------------------------------------------*/
+
+var (
+	// where the clock of a recorder starts
+	Epoch = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+)
+
+/* ----------------------------------------------------------------
+ *							T y p e s
+ *-----------------------------------------------------------------*/
+
+// Record is a captured log entry.
+type Record struct {
+	Time    time.Time
+	Level   mlog.LogLevel
+	Message string
+	Tags    []Tag
+	Caller  *mlog.CallerInfo // the call site, nil if unknown
+}
+
+// Tag is a key=value pair of a record. Quoted values are unquoted.
+type Tag struct {
+	Key   string
+	Value string
+}
+
+// Clock is a fake clock which only moves when told to.
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// Recorder captures the entries of a logger.
+type Recorder struct {
+	Clock *Clock
+
+	l        *mlog.Logger
+	mu       sync.Mutex
+	records  []Record
+	exitCode int
+	exited   bool
+}
+
+/* ----------------------------------------------------------------
+ *							C o n s t r u c t o r s
+ *-----------------------------------------------------------------*/
+
+// NewClock creates a clock set at the given time.
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+// NewRecorder captures the entries of the logger (the default logger
+// if nil) until the test ends, those its main output would get at its
+// level and per-package overrides. Meanwhile that output is discarded,
+// its clock starts at Epoch and the Fatal functions of the whole
+// package record their exit code instead of exiting. Tests using a
+// recorder must not run in parallel.
+func NewRecorder(t testing.TB, l *mlog.Logger) *Recorder {
+	t.Helper()
+	if l == nil {
+		l = mlog.Default()
+	}
+
+	r := &Recorder{Clock: NewClock(Epoch), l: l}
+	out := l.Output()
+	l.SetOutput(io.Discard)
+	l.SetClock(r.Clock.Now)
+	sink := l.AddMainRecordSink(r.add)
+	previousExit := mlog.SetExitFunc(r.exit)
+
+	t.Cleanup(func() {
+		l.Flush()
+		mlog.SetExitFunc(previousExit)
+		l.RemoveSink(sink)
+		l.SetClock(nil)
+		l.SetOutput(out)
+	})
+	return r
+}
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// Now returns the current time of the clock.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// Advance moves the clock forward.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// Set moves the clock to the given time.
+func (c *Clock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = now
+}
+
+// implements fmt.Stringer
+func (r Record) String() string {
+	var sb strings.Builder
+	sb.WriteString(levelName(r.Level) + " " + r.Message)
+	for _, t := range r.Tags {
+		sb.WriteString(" " + t.Key + "=" + t.Value)
+	}
+	return sb.String()
+}
+
+// Get returns the value of the first tag with the given key.
+func (r Record) Get(key string) (string, bool) {
+	for _, t := range r.Tags {
+		if t.Key == key {
+			return t.Value, true
+		}
+	}
+	return "", false
+}
+
+// Records returns a copy of the records captured so far.
+func (r *Recorder) Records() []Record {
+	r.l.Flush()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Record(nil), r.records...)
+}
+
+// Reset forgets the records and the exit code captured so far.
+func (r *Recorder) Reset() {
+	r.l.Flush()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records = nil
+	r.exitCode, r.exited = 0, false
+}
+
+// ExitCode returns the code of the last Fatal call, if any.
+func (r *Recorder) ExitCode() (int, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.exitCode, r.exited
+}
+
+// Find returns the records at the given level whose message contains
+// substr and which have the given tags. keyValues are key and value
+// pairs; an odd last key only needs to be present.
+func (r *Recorder) Find(level mlog.LogLevel, substr string, keyValues ...string) []Record {
+	var found []Record = nil
+	for _, rec := range r.Records() {
+		if rec.Level == level && strings.Contains(rec.Message, substr) && hasTags(rec, keyValues) {
+			found = append(found, rec)
+		}
+	}
+	return found
+}
+
+// AssertLogged fails the test unless an entry at the given level
+// contains substr in its message and has the given tags (see Find()).
+func (r *Recorder) AssertLogged(t testing.TB, level mlog.LogLevel, substr string, keyValues ...string) {
+	t.Helper()
+	if len(r.Find(level, substr, keyValues...)) == 0 {
+		t.Errorf("no %s entry with %q%s was logged%s", levelName(level), substr, describeTags(keyValues), r.dump())
+	}
+}
+
+// AssertNotLogged fails the test if an entry at the given level
+// contains substr in its message and has the given tags.
+func (r *Recorder) AssertNotLogged(t testing.TB, level mlog.LogLevel, substr string, keyValues ...string) {
+	t.Helper()
+	if found := r.Find(level, substr, keyValues...); len(found) > 0 {
+		t.Errorf("unexpected %s entry was logged: %s", levelName(level), found[0])
+	}
+}
+
+// AssertExited fails the test unless a Fatal function exited with the
+// given code.
+func (r *Recorder) AssertExited(t testing.TB, code int) {
+	t.Helper()
+	if got, exited := r.ExitCode(); !exited {
+		t.Errorf("no Fatal function was called, expected exit code %d", code)
+	} else if got != code {
+		t.Errorf("exit code %d, expected %d", got, code)
+	}
+}
+
+// keeps a record, called by the logger's record sink
+func (r *Recorder) add(rec mlog.Record) {
+	captured := Record{Time: rec.Time, Level: rec.Level, Message: rec.Message, Caller: rec.Caller}
+	for _, t := range rec.Rendered {
+		captured.Tags = append(captured.Tags, Tag{t.Key, t.Value})
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, captured)
+}
+
+// replaces os.Exit()
+func (r *Recorder) exit(code int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.exitCode, r.exited = code, true
+}
+
+// the captured records, one per line, for failure messages
+func (r *Recorder) dump() string {
+	records := r.Records()
+	if len(records) == 0 {
+		return ", nothing was"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(". Logged:")
+	for _, rec := range records {
+		sb.WriteString("\n\t" + rec.String())
+	}
+	return sb.String()
+}
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// whether the record has the key and value pairs
+func hasTags(rec Record, keyValues []string) bool {
+	for i := 0; i < len(keyValues); i += 2 {
+		value, found := rec.Get(keyValues[i])
+		if !found || i+1 < len(keyValues) && value != keyValues[i+1] {
+			return false
+		}
+	}
+	return true
+}
+
+// the key and value pairs for failure messages
+func describeTags(keyValues []string) string {
+	if len(keyValues) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, 0, (len(keyValues)+1)/2)
+	for i := 0; i < len(keyValues); i += 2 {
+		if i+1 < len(keyValues) {
+			pairs = append(pairs, keyValues[i]+"="+keyValues[i+1])
+		} else {
+			pairs = append(pairs, keyValues[i])
+		}
+	}
+	return fmt.Sprintf(" and %s", strings.Join(pairs, " "))
+}
+
+// the name of a level, i.e. Warning
+func levelName(level mlog.LogLevel) string {
+	switch level {
+	case mlog.LevelTrace:
+		return "Trace"
+	case mlog.LevelDebug:
+		return "Debug"
+	case mlog.LevelInfo:
+		return "Info"
+	case mlog.LevelWarning:
+		return "Warning"
+	case mlog.LevelError:
+		return "Error"
+	case mlog.LevelFatal:
+		return "Fatal"
+	}
+	return fmt.Sprintf("Level(%d)", int(level))
+}
@@ -0,0 +1,154 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Tests of the recorder: assertions, fake clock, Fatal interception
+ * and the level of the recorded logger.
+ *-----------------------------------------------------------------*/
+package mlogtest
+
+import (
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/lordofscripts/goapp/app/mlog"
+)
+
+/* ----------------------------------------------------------------
+ *							T y p e s
+ *-----------------------------------------------------------------*/
+
+// a testing.TB which keeps the failures of the assertions under test
+type fakeT struct {
+	testing.TB
+	failures []string
+}
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...any) {
+	f.failures = append(f.failures, fmt.Sprintf(format, args...))
+}
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+func TestAssertLogged(t *testing.T) {
+	l := mlog.NewLogger(io.Discard, "", mlog.LevelWarning)
+	rec := NewRecorder(t, l)
+	l.WarnT("retrying", mlog.Int("Attempt", 2), mlog.String("Host", "db 1"))
+
+	rec.AssertLogged(t, mlog.LevelWarning, "retry")
+	rec.AssertLogged(t, mlog.LevelWarning, "retrying", "Attempt", "2", "Host", "db 1")
+	rec.AssertLogged(t, mlog.LevelWarning, "", "Host")
+	rec.AssertNotLogged(t, mlog.LevelError, "")
+	rec.AssertNotLogged(t, mlog.LevelWarning, "retrying", "Attempt", "3")
+
+	ft := &fakeT{TB: t}
+	rec.AssertLogged(ft, mlog.LevelError, "retrying")
+	rec.AssertLogged(ft, mlog.LevelWarning, "retrying", "Missing")
+	rec.AssertNotLogged(ft, mlog.LevelWarning, "retrying", "Attempt", "2")
+	if len(ft.failures) != 3 {
+		t.Errorf("%d failures, want 3: %q", len(ft.failures), ft.failures)
+	}
+}
+
+func TestRecordedTags(t *testing.T) {
+	l := mlog.NewLogger(io.Discard, "", mlog.LevelWarning)
+	rec := NewRecorder(t, l)
+
+	calls := 0
+	l.With(mlog.String("Request", "r1")).WarnT("lazy", mlog.Lazy("Dump", func() string {
+		calls++
+		return "big"
+	}))
+
+	records := rec.Records()
+	if len(records) != 1 {
+		t.Fatalf("%d records, want 1", len(records))
+	}
+	want := []Tag{{"Request", "r1"}, {"Dump", "big"}}
+	if got := records[0].Tags; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("tags %v, want %v", got, want)
+	}
+	if calls != 1 {
+		t.Errorf("the lazy tag ran %d times, want once", calls)
+	}
+}
+
+func TestClock(t *testing.T) {
+	l := mlog.NewLogger(io.Discard, "", mlog.LevelWarning)
+	rec := NewRecorder(t, l)
+
+	l.Warn("first")
+	rec.Clock.Advance(90 * time.Second)
+	l.Warn("second")
+	rec.Clock.Set(Epoch.Add(time.Hour))
+	l.Warn("third")
+
+	records := rec.Records()
+	want := []time.Time{Epoch, Epoch.Add(90 * time.Second), Epoch.Add(time.Hour)}
+	if len(records) != len(want) {
+		t.Fatalf("%d records, want %d", len(records), len(want))
+	}
+	for i, r := range records {
+		if !r.Time.Equal(want[i]) {
+			t.Errorf("%s at %v, want %v", r.Message, r.Time, want[i])
+		}
+	}
+}
+
+func TestFatal(t *testing.T) {
+	l := mlog.NewLogger(io.Discard, "", mlog.LevelWarning)
+	rec := NewRecorder(t, l)
+
+	ft := &fakeT{TB: t}
+	rec.AssertExited(ft, 3)
+	if len(ft.failures) != 1 {
+		t.Errorf("AssertExited passed before any Fatal call")
+	}
+
+	l.Fatal(3, "giving up")
+	rec.AssertExited(t, 3)
+	rec.AssertLogged(t, mlog.LevelFatal, "giving up")
+
+	ft = &fakeT{TB: t}
+	rec.AssertExited(ft, 4)
+	if len(ft.failures) != 1 {
+		t.Errorf("AssertExited passed with the wrong code")
+	}
+
+	rec.Reset()
+	if _, exited := rec.ExitCode(); exited || len(rec.Records()) != 0 {
+		t.Error("Reset kept the exit code or the records")
+	}
+}
+
+func TestLevel(t *testing.T) {
+	l := mlog.NewLogger(io.Discard, "", mlog.LevelError)
+	// a sink taking more than the main output must not widen the recorder
+	l.AddRecordSink(func(mlog.Record) {}, mlog.LevelWarning)
+	rec := NewRecorder(t, l)
+
+	l.Warn("filtered")
+	l.Error("kept")
+
+	rec.AssertNotLogged(t, mlog.LevelWarning, "filtered")
+	rec.AssertLogged(t, mlog.LevelError, "kept")
+
+	l.SetLevel(mlog.LevelWarning)
+	l.Warn("now kept")
+	rec.AssertLogged(t, mlog.LevelWarning, "now kept")
+
+	l.SetLevel(mlog.LevelFatal)
+	l.Error("dropped")
+	rec.AssertNotLogged(t, mlog.LevelError, "dropped")
+}
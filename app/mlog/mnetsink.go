@@ -0,0 +1,484 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Network sink shipping entries to a TCP or UDP collector as text
+ * lines or GELF. A background goroutine does the sending, reconnects
+ * with exponential backoff and spools the entries to a local file
+ * while the collector is unreachable, replaying them in order once it
+ * is back. The callers never wait longer than a deadline.
+ *-----------------------------------------------------------------*/
+package mlog
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/* ----------------------------------------------------------------
+ *							G l o b a l s
+ *-----------------------------------------------------------------*/
+
+const (
+	defaultNetDeadline   time.Duration = 50 * time.Millisecond
+	defaultNetQueueSize  int           = 1024
+	defaultNetMinBackoff time.Duration = 500 * time.Millisecond
+	defaultNetMaxBackoff time.Duration = 30 * time.Second
+	defaultNetMaxSpool   int64         = 64 << 20
+	// longest dial or write of the background goroutine
+	netIOTimeout time.Duration = 5 * time.Second
+
+	// timestamp of the text lines
+	netTimeFormat string = "2006-01-02T15:04:05.000Z07:00"
+
+	// length of the big endian size before each spooled entry
+	spoolHeaderSize int = 4
+
+	// GELF over UDP: largest datagram and chunk count
+	gelfChunkSize int = 8192
+	gelfMaxChunks int = 128
+)
+
+// Network sink format enumeration
+const (
+	NetText   NetFormat = iota // "time [LVL] message tags" lines
+	NetGELF                    // Graylog Extended Log Format 1.1
+	NetLogfmt                  // logfmt lines, see EncodeLogfmt
+	NetJSON                    // JSON lines, see EncodeJSON
+)
+
+var (
+	// characters not allowed in a GELF field name
+	gelfFieldChars = regexp.MustCompile(`[^\w.\-]`)
+)
+
+/* ----------------------------------------------------------------
+ *							T y p e s
+ *-----------------------------------------------------------------*/
+
+// NetFormat is how a network sink encodes the entries
+type NetFormat int
+
+// NetOptions describe the collector of a network sink and how it is
+// fed. Only Network and Address are required.
+type NetOptions struct {
+	Network    string        // "tcp" or "udp"
+	Address    string        // host:port of the collector
+	Format     NetFormat     // NetText, NetLogfmt, NetJSON (newline delimited) or NetGELF
+	Layout     *Layout       // of the NetText lines, nil for the default
+	SpoolFile  string        // keeps the entries while the collector is down, "" drops them
+	MaxSpool   int64         // largest spool file (bytes), 0 for 64MB; newer entries are dropped
+	Deadline   time.Duration // longest the caller may wait, 0 for 50ms
+	QueueSize  int           // entries waiting to be sent, 0 for 1024
+	MinBackoff time.Duration // first reconnection delay, 0 for 500ms
+	MaxBackoff time.Duration // longest reconnection delay, 0 for 30s
+	Host       string        // GELF host, "" for os.Hostname()
+}
+
+// ships the encoded entries to the collector
+type netSink struct {
+	opts    NetOptions
+	queue   chan []byte
+	mu      sync.RWMutex // closed vs. enqueuing
+	closed  bool
+	done    chan struct{}
+	dropped atomic.Uint64 // entries lost since the last report
+
+	// owned by the background goroutine
+	conn      net.Conn
+	timer     *time.Timer // reconnection, armed while disconnected
+	backoff   time.Duration
+	spool     *os.File
+	spoolSize int64
+	spoolSent int64 // bytes of the spool already replayed
+}
+
+/* ----------------------------------------------------------------
+ *							C o n s t r u c t o r s
+ *-----------------------------------------------------------------*/
+
+// creates the sink and starts its goroutine, which connects right away
+func newNetSink(opts NetOptions) (*netSink, error) {
+	switch opts.Network {
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6":
+	default:
+		return nil, fmt.Errorf("mlog: unsupported network %q", opts.Network)
+	}
+	if opts.Deadline <= 0 {
+		opts.Deadline = defaultNetDeadline
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = defaultNetQueueSize
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = defaultNetMinBackoff
+	}
+	if opts.MaxBackoff < opts.MinBackoff {
+		opts.MaxBackoff = defaultNetMaxBackoff
+	}
+	if opts.MaxSpool <= 0 {
+		opts.MaxSpool = defaultNetMaxSpool
+	}
+	if opts.Host == "" {
+		opts.Host, _ = os.Hostname()
+	}
+
+	n := &netSink{opts: opts, queue: make(chan []byte, opts.QueueSize), done: make(chan struct{})}
+	if opts.SpoolFile != "" {
+		// what a previous run left over goes first
+		spool, err := os.OpenFile(opts.SpoolFile, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+		if err != nil {
+			return nil, err
+		}
+		info, err := spool.Stat()
+		if err != nil {
+			spool.Close()
+			return nil, err
+		}
+		n.spool, n.spoolSize = spool, info.Size()
+	}
+
+	// armed by the first failed connection
+	n.timer = time.NewTimer(time.Hour)
+	n.timer.Stop()
+	go n.run()
+	return n, nil
+}
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// hands the entry to the goroutine, waiting at most the deadline for
+// room in the queue
+func (n *netSink) forward(r *record) {
+	payload := n.encode(r)
+
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	if n.closed {
+		return
+	}
+	select {
+	case n.queue <- payload:
+		return
+	default:
+	}
+
+	timer := time.NewTimer(n.opts.Deadline)
+	defer timer.Stop()
+	select {
+	case n.queue <- payload:
+	case <-timer.C:
+		n.dropped.Add(1)
+	}
+}
+
+// implements io.Closer: sends (or spools) what is queued and stops
+func (n *netSink) Close() error {
+	n.mu.Lock()
+	if n.closed {
+		n.mu.Unlock()
+		return nil
+	}
+	n.closed = true
+	close(n.queue)
+	n.mu.Unlock()
+
+	<-n.done
+	return nil
+}
+
+// the background goroutine. It connects before taking the first entry
+// so that those logged right away are not spooled or dropped.
+func (n *netSink) run() {
+	defer close(n.done)
+	n.connect()
+	for {
+		select {
+		case payload, ok := <-n.queue:
+			if !ok {
+				n.shutdown()
+				return
+			}
+			n.deliver(payload)
+
+		case <-n.timer.C:
+			n.connect()
+		}
+	}
+}
+
+// sends the payload if connected and nothing is spooled, else spools it
+func (n *netSink) deliver(payload []byte) {
+	if n.conn != nil && n.spoolSent == n.spoolSize {
+		written, err := n.send(payload)
+		if err == nil {
+			n.reportDropped()
+			return
+		}
+		n.disconnect()
+		if written > 0 {
+			// part of the line went out, sending it again would
+			// give the collector a torn line and then a duplicate
+			n.dropped.Add(1)
+			return
+		}
+	}
+
+	record := int64(spoolHeaderSize + len(payload))
+	if n.spool == nil || n.spoolSize+record > n.opts.MaxSpool {
+		n.dropped.Add(1)
+		return
+	}
+	// length-prefixed, entries may have newlines
+	framed := make([]byte, spoolHeaderSize, record)
+	binary.BigEndian.PutUint32(framed, uint32(len(payload)))
+	written, err := n.spool.Write(append(framed, payload...))
+	n.spoolSize += int64(written)
+	if err != nil {
+		n.dropped.Add(1)
+	}
+}
+
+// dials the collector and replays the spool. On failure it tries
+// again later, waiting twice as long each time.
+func (n *netSink) connect() {
+	conn, err := net.DialTimeout(n.opts.Network, n.opts.Address, netIOTimeout)
+	if err != nil {
+		n.retryLater()
+		return
+	}
+	n.conn, n.backoff = conn, 0
+
+	if err := n.replay(); err != nil {
+		n.disconnect()
+		return
+	}
+	n.reportDropped()
+}
+
+// tells the collector how many entries were lost, if any
+func (n *netSink) reportDropped() {
+	if dropped := n.dropped.Swap(0); dropped > 0 {
+		notice := newRecord(LevelWarning, fmt.Sprintf("mlog: dropped %d entries for %s", dropped, n.opts.Address))
+		if _, err := n.send(n.encode(notice)); err != nil {
+			n.dropped.Add(dropped)
+			n.disconnect()
+		}
+	}
+}
+
+// arms the reconnection timer with the next backoff delay
+func (n *netSink) retryLater() {
+	if n.backoff == 0 {
+		n.backoff = n.opts.MinBackoff
+	} else if n.backoff *= 2; n.backoff > n.opts.MaxBackoff {
+		n.backoff = n.opts.MaxBackoff
+	}
+	n.timer.Reset(n.backoff)
+}
+
+// drops the broken connection and reconnects later
+func (n *netSink) disconnect() {
+	if n.conn != nil {
+		n.conn.Close()
+		n.conn = nil
+	}
+	n.retryLater()
+}
+
+// sends the spooled entries in order and empties the spool
+func (n *netSink) replay() error {
+	if n.spool == nil || n.spoolSent == n.spoolSize {
+		return nil
+	}
+
+	r := bufio.NewReader(io.NewSectionReader(n.spool, n.spoolSent, n.spoolSize-n.spoolSent))
+	header := make([]byte, spoolHeaderSize)
+	for {
+		// a torn entry at the end (a crash while spooling) is dropped
+		if _, err := io.ReadFull(r, header); err != nil {
+			break
+		}
+		payload := make([]byte, binary.BigEndian.Uint32(header))
+		if _, err := io.ReadFull(r, payload); err != nil {
+			break
+		}
+		written, err := n.send(payload)
+		if written > 0 || err == nil {
+			// a torn entry is not sent again, see deliver()
+			n.spoolSent += int64(spoolHeaderSize + len(payload))
+		}
+		if err != nil {
+			if written > 0 {
+				n.dropped.Add(1)
+			}
+			return err
+		}
+	}
+
+	if err := n.spool.Truncate(0); err != nil {
+		return err
+	}
+	n.spoolSize, n.spoolSent = 0, 0
+	return nil
+}
+
+// writes a payload framed for the network and format. On failure the
+// number of bytes written tells whether part of it went out over a
+// stream; a datagram or an incomplete set of GELF chunks is discarded
+// by the collector.
+func (n *netSink) send(payload []byte) (int, error) {
+	n.conn.SetWriteDeadline(time.Now().Add(netIOTimeout))
+	if !n.isStream() {
+		if n.opts.Format == NetGELF && len(payload) > gelfChunkSize {
+			return 0, n.sendChunks(payload)
+		}
+		if _, err := n.conn.Write(payload); err != nil {
+			return 0, err
+		}
+		return len(payload), nil
+	}
+
+	delimiter := byte('\n')
+	if n.opts.Format == NetGELF {
+		delimiter = 0
+	}
+	return n.conn.Write(append(payload, delimiter))
+}
+
+// sends a big GELF message as UDP chunks
+func (n *netSink) sendChunks(payload []byte) error {
+	const headerSize = 12
+	dataSize := gelfChunkSize - headerSize
+	count := (len(payload) + dataSize - 1) / dataSize
+	if count > gelfMaxChunks {
+		return errors.New("mlog: GELF message too big")
+	}
+
+	var id [8]byte
+	rand.Read(id[:])
+	for seq := 0; seq < count; seq++ {
+		end := (seq + 1) * dataSize
+		if end > len(payload) {
+			end = len(payload)
+		}
+		chunk := append([]byte{0x1e, 0x0f}, id[:]...)
+		chunk = append(chunk, byte(seq), byte(count))
+		chunk = append(chunk, payload[seq*dataSize:end]...)
+		if _, err := n.conn.Write(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sends what is left and releases the connection and the spool
+func (n *netSink) shutdown() {
+	n.timer.Stop()
+	if n.conn != nil {
+		if n.replay() == nil {
+			n.dropped.Store(0)
+		}
+		n.conn.Close()
+		n.conn = nil
+	}
+
+	if n.spool != nil {
+		n.spool.Close()
+		if n.spoolSize == 0 {
+			os.Remove(n.opts.SpoolFile)
+		}
+	}
+}
+
+// whether the connection is a stream (TCP) rather than datagrams
+func (n *netSink) isStream() bool {
+	switch n.opts.Network {
+	case "tcp", "tcp4", "tcp6":
+		return true
+	}
+	return false
+}
+
+// the entry as a text line or GELF message, without delimiter
+func (n *netSink) encode(r *record) []byte {
+	switch n.opts.Format {
+	case NetLogfmt:
+		return EncodeLogfmt.encode("", r)
+	case NetJSON:
+		return EncodeJSON.encode("", r)
+	case NetText:
+		if n.opts.Layout != nil {
+			return []byte(n.opts.Layout.format("", r, nil))
+		}
+		return []byte(r.time.Format(netTimeFormat) + " " + r.line)
+	}
+
+	message := map[string]any{
+		"version":       "1.1",
+		"host":          n.opts.Host,
+		"short_message": r.message,
+		"timestamp":     float64(r.time.UnixMilli()) / 1000,
+		"level":         syslogSeverity(r.level),
+	}
+	for _, rendered := range r.tagText {
+		key, value, found := splitRendered(rendered)
+		if !found {
+			key = "tag"
+		}
+		key = "_" + gelfFieldChars.ReplaceAllString(key, "_")
+		if key == "_id" {
+			key = "_tag_id"
+		}
+		message[key] = value
+	}
+
+	payload, _ := json.Marshal(message)
+	return payload
+}
+
+// AddNetSink ships every entry at minLevel or above to a TCP or UDP
+// collector. While it is unreachable the entries wait in the spool
+// file, if any, or are dropped; a warning reports how many once it is
+// back. Close() (or CloseLogFiles()) sends what is queued and stops.
+func (l *Logger) AddNetSink(opts NetOptions, minLevel LogLevel) (*Sink, error) {
+	n, err := newNetSink(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Sink{minLevel: minLevel, forward: n.forward, closer: n, unlocked: true}
+	switch opts.Format {
+	case NetLogfmt, NetJSON:
+		s.caller = true
+	case NetText:
+		s.caller = opts.Layout != nil && opts.Layout.caller
+	}
+	l.addSink(s)
+	return s, nil
+}
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// AddNetSink ships the entries of the default logger to a collector.
+// See Logger.AddNetSink().
+func AddNetSink(opts NetOptions, minLevel LogLevel) (*Sink, error) {
+	return std.AddNetSink(opts, minLevel)
+}
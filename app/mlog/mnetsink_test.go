@@ -0,0 +1,216 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Tests of the network sink against a local TCP collector: spooling
+ * while it is down, replay order once it is back and the deadline of
+ * the callers while it does not read.
+ *-----------------------------------------------------------------*/
+package mlog
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// an address of a local TCP port nobody listens on (yet)
+func unusedAddress(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	return addr
+}
+
+// waits up to 5s for the condition
+func waitFor(t *testing.T, what string, cond func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for " + what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// the size of a file, -1 if missing
+func fileSize(name string) int64 {
+	info, err := os.Stat(name)
+	if err != nil {
+		return -1
+	}
+	return info.Size()
+}
+
+// a collector returning everything sent on its first connection
+func collect(t *testing.T, ln net.Listener) <-chan string {
+	received := make(chan string, 1)
+	go func() {
+		defer close(received)
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		b, _ := io.ReadAll(conn)
+		received <- string(b)
+	}()
+	return received
+}
+
+// a logger shipping the messages alone to addr
+func netLogger(t *testing.T, opts NetOptions) *Logger {
+	layout, err := ParseLayout("{msg}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.Network, opts.Layout = "tcp", layout
+	l := NewLogger(io.Discard, "", LevelWarning)
+	if _, err := l.AddNetSink(opts, LevelWarning); err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func TestNetSinkFirstEntries(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	received := collect(t, ln)
+
+	l := netLogger(t, NetOptions{Address: ln.Addr().String()})
+	l.Warn("first")
+	l.Close()
+
+	if got := <-received; got != "first\n" {
+		t.Errorf("received %q, want %q", got, "first\n")
+	}
+}
+
+func TestNetSinkSpoolReplay(t *testing.T) {
+	addr := unusedAddress(t)
+	spool := filepath.Join(t.TempDir(), "net.spool")
+	l := netLogger(t, NetOptions{
+		Address:    addr,
+		SpoolFile:  spool,
+		MinBackoff: 10 * time.Millisecond,
+		MaxBackoff: 20 * time.Millisecond,
+	})
+
+	var want strings.Builder
+	var spooled int64
+	for i := 0; i < 5; i++ {
+		message := fmt.Sprintf("entry %d\n\tcontinued", i)
+		l.Warn(message)
+		want.WriteString(message + "\n")
+		spooled += int64(spoolHeaderSize + len(message))
+	}
+
+	// everything is spooled before the collector comes up
+	waitFor(t, "the spool", func() bool { return fileSize(spool) == spooled })
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("cannot listen on %s again: %v", addr, err)
+	}
+	defer ln.Close()
+	received := collect(t, ln)
+	waitFor(t, "the replay", func() bool { return fileSize(spool) == 0 })
+
+	l.Warn("after")
+	want.WriteString("after\n")
+	l.Close()
+
+	if got := <-received; got != want.String() {
+		t.Errorf("received %q, want %q", got, want.String())
+	}
+	if _, err := os.Stat(spool); !os.IsNotExist(err) {
+		t.Errorf("spool file left behind: %v", err)
+	}
+}
+
+func TestNetSinkMaxSpool(t *testing.T) {
+	spool := filepath.Join(t.TempDir(), "net.spool")
+	l := netLogger(t, NetOptions{
+		Address:    unusedAddress(t),
+		SpoolFile:  spool,
+		MaxSpool:   int64(2 * (spoolHeaderSize + len("entry 0"))),
+		MinBackoff: time.Hour,
+	})
+	for i := 0; i < 5; i++ {
+		l.Warnf("entry %d", i)
+	}
+	l.Close()
+
+	info, err := os.Stat(spool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != 2*int64(spoolHeaderSize+len("entry 0")) {
+		t.Errorf("spool of %d bytes, want the first 2 entries", info.Size())
+	}
+}
+
+func TestNetSinkDeadline(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	// accepts but never reads so the socket buffers fill up
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		if conn, err := ln.Accept(); err == nil {
+			accepted <- conn
+		}
+	}()
+
+	const deadline = 20 * time.Millisecond
+	l := netLogger(t, NetOptions{Address: ln.Addr().String(), Deadline: deadline, QueueSize: 1})
+	n := l.Sinks()[0].closer.(*netSink)
+
+	// small enough that formatting takes a fraction of the deadline
+	message := strings.Repeat("x", 64<<10)
+	var slowest, last time.Duration
+	for i := 0; i < 1024 && n.dropped.Load() == 0; i++ {
+		start := time.Now()
+		l.Warn(message)
+		last = time.Since(start)
+		if last > slowest {
+			slowest = last
+		}
+	}
+
+	if n.dropped.Load() == 0 {
+		t.Error("the collector never held the sink up")
+	} else if last < deadline {
+		// the call which dropped its entry waited the deadline out
+		t.Errorf("the entry was dropped after %v, deadline %v", last, deadline)
+	}
+	if slowest > deadline+50*time.Millisecond {
+		t.Errorf("a caller waited %v, deadline %v", slowest, deadline)
+	}
+
+	// let the sink fail fast and finish
+	select {
+	case conn := <-accepted:
+		conn.Close()
+	case <-time.After(time.Second):
+	}
+	l.Close()
+}
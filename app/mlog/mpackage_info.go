@@ -67,20 +67,37 @@ func RetrievePackageInfo(frameNr ...FrameNr) *PackageInfo {
 	if ok {
 		pif := &PackageInfo{"", fileName}
 
-		funcName := runtime.FuncForPC(pc).Name()
-		lastSlash := strings.LastIndexByte(funcName, '/')
-		if lastSlash < 0 {
-			lastSlash = 0
-		}
-		lastDot := strings.LastIndexByte(funcName[lastSlash:], '.') + lastSlash
-		if idx := strings.IndexByte(funcName[:lastDot], '.'); idx > -1 {
-			pif.Package = funcName[:idx]
-		} else {
-			pif.Package = funcName[:lastDot]
-		}
-
+		pif.Package = packageOfFunction(runtime.FuncForPC(pc).Name())
 		return pif
 	}
 
 	return nil
 }
+
+// the package part of a fully-qualified function name such as
+// "github.com/acme/app/ui.(*Window).Show"
+func packageOfFunction(funcName string) string {
+	pkg, _, _ := splitFuncName(funcName)
+	return pkg
+}
+
+// splits a fully-qualified function name into its package, receiver
+// (or enclosing function) and function. The package path may have dots
+// too (github.com/...), only those after its last slash separate them.
+func splitFuncName(funcName string) (string, string, string) {
+	lastSlash := strings.LastIndexByte(funcName, '/')
+	if lastSlash < 0 {
+		lastSlash = 0
+	}
+	lastDot := strings.LastIndexByte(funcName[lastSlash:], '.')
+	if lastDot < 0 {
+		return funcName, "", ""
+	}
+	lastDot += lastSlash
+
+	if idx := strings.IndexByte(funcName[lastSlash:lastDot], '.'); idx > -1 {
+		idx += lastSlash
+		return funcName[:idx], funcName[idx+1 : lastDot], funcName[lastDot+1:]
+	}
+	return funcName[:lastDot], "", funcName[lastDot+1:]
+}
@@ -0,0 +1,200 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Secret redaction. The Secret tags never show their value, and the
+ * global redaction rules mask the tags whose key matches a pattern
+ * as well as anything matching a value expression (card numbers,
+ * tokens, etc.) in messages, tags and catheter lines.
+ *-----------------------------------------------------------------*/
+package mlog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+/* ----------------------------------------------------------------
+ *							G l o b a l s
+ *-----------------------------------------------------------------*/
+
+const redactMask string = "****"
+
+var (
+	// Payment card numbers: 13 to 19 digits, optionally grouped
+	// with spaces or dashes.
+	PatternCardNumber = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+	// HTTP bearer tokens
+	PatternBearerToken = regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/-]+=*`)
+	// JSON Web Tokens (three base64url segments)
+	PatternJWT = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+)
+
+var (
+	redaction   atomic.Pointer[redactRules] // nil when there are no rules
+	redactionMu sync.Mutex                  // serializes rule changes
+)
+
+var _ ILogKeyValuePair = (*kvSecret)(nil)
+var _ ILogKeyValuePair = (*kvRendered)(nil)
+
+/* ----------------------------------------------------------------
+ *							T y p e s
+ *-----------------------------------------------------------------*/
+
+// the global redaction rules, replaced as a whole on every change
+type redactRules struct {
+	keys   []string // lower-case key patterns with '*' wildcards
+	values []*regexp.Regexp
+}
+
+type kvSecret struct {
+	k      string
+	v      string
+	hashed bool
+}
+
+// a tag already rendered (and redacted) as key=value
+type kvRendered struct {
+	s string
+}
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// implements fmt.Stringer for mlog.Secret() and mlog.SecretHashed()
+func (k *kvSecret) String() string {
+	if !k.hashed {
+		return k.k + "=" + redactMask
+	}
+	sum := sha256.Sum256([]byte(k.v))
+	return k.k + "=" + redactMask + "#" + hex.EncodeToString(sum[:4])
+}
+
+// implements fmt.Stringer
+func (k *kvRendered) String() string {
+	return k.s
+}
+
+// masks every value expression match in s
+func (r *redactRules) text(s string) string {
+	for _, re := range r.values {
+		s = re.ReplaceAllLiteralString(s, redactMask)
+	}
+	return s
+}
+
+// redacts a tag rendered as key=value. The whole value is masked if
+// the key matches, else only the value expression matches.
+func (r *redactRules) tag(rendered string) string {
+	key, value, found := strings.Cut(rendered, "=")
+	if !found {
+		return r.text(rendered)
+	}
+
+	lowKey := strings.ToLower(key)
+	for _, pattern := range r.keys {
+		if pattern == lowKey || wildcardMatch(pattern, lowKey) {
+			return key + "=" + redactMask
+		}
+	}
+	return key + "=" + r.text(value)
+}
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// log a secret (password, cipher key...) as key=**** so its value
+// never reaches the log.
+func Secret(key, value string) ILogKeyValuePair {
+	return &kvSecret{key, value, false}
+}
+
+// log a secret as key=****#fingerprint where the fingerprint is the
+// beginning of its SHA-256 hash. It tells whether two secrets are the
+// same without revealing them.
+func SecretHashed(key, value string) ILogKeyValuePair {
+	return &kvSecret{key, value, true}
+}
+
+// RedactKeys masks the value of every tag whose key matches one of the
+// case-insensitive patterns, i.e. "password", "*token*" or "*key".
+func RedactKeys(patterns ...string) {
+	updateRedaction(func(r *redactRules) {
+		for _, p := range patterns {
+			r.keys = append(r.keys, strings.ToLower(p))
+		}
+	})
+}
+
+// RedactValues masks whatever matches the expressions in messages,
+// tags and catheter lines, i.e. mlog.PatternCardNumber.
+func RedactValues(expressions ...*regexp.Regexp) {
+	updateRedaction(func(r *redactRules) {
+		r.values = append(r.values, expressions...)
+	})
+}
+
+// ClearRedaction removes all the redaction rules. Secret tags are
+// still masked.
+func ClearRedaction() {
+	redactionMu.Lock()
+	defer redactionMu.Unlock()
+
+	redaction.Store(nil)
+}
+
+// applies a change to a copy of the current rules and publishes it
+func updateRedaction(change func(*redactRules)) {
+	redactionMu.Lock()
+	defer redactionMu.Unlock()
+
+	rules := &redactRules{}
+	if current := redaction.Load(); current != nil {
+		rules.keys = append(rules.keys, current.keys...)
+		rules.values = append(rules.values, current.values...)
+	}
+	change(rules)
+	redaction.Store(rules)
+}
+
+// formats an entry as level tag, message and tags applying the
+// redaction rules. It returns the line along with the (redacted)
+// message, tags and tag renderings; tags that needed redaction are
+// replaced by their redacted rendering. Each tag is rendered only once.
+func formatEntry(levelTag, message string, tags []ILogKeyValuePair) (string, string, []ILogKeyValuePair, []string) {
+	rules := redaction.Load()
+	if rules != nil {
+		message = rules.text(message)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(levelTag)
+	sb.WriteString(message)
+	rendered := make([]string, len(tags))
+	copied := false
+	for i, t := range tags {
+		s := t.String()
+		if _, isSecret := t.(*kvSecret); rules != nil && !isSecret {
+			if redacted := rules.tag(s); redacted != s {
+				if !copied {
+					tags = append([]ILogKeyValuePair(nil), tags...)
+					copied = true
+				}
+				s = redacted
+				tags[i] = &kvRendered{s}
+			}
+		}
+		rendered[i] = s
+		sb.WriteString(" " + s)
+	}
+
+	return sb.String(), message, tags, rendered
+}
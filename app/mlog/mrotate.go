@@ -0,0 +1,411 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Rotating log file writer. The file is rotated when it reaches its
+ * maximum size and/or on a daily or hourly schedule. Rotated files
+ * get a timestamped name, may be gzipped and only a limited number
+ * of generations is kept.
+ *-----------------------------------------------------------------*/
+package mlog
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/* ----------------------------------------------------------------
+ *							G l o b a l s
+ *-----------------------------------------------------------------*/
+
+const (
+	// environment variable with the log rotation spec for CaesarX,
+	// i.e. "size=10M,daily,keep=5,gzip"
+	LOG_ROTATE_ENV string = "LOG_ROTATE_CX"
+
+	// timestamp inserted in the name of the rotated files
+	backupTimeFormat string = "20060102T150405.000"
+)
+
+const (
+	// Rotation schedule enumeration
+	RotateNever RotateInterval = iota
+	RotateHourly
+	RotateDaily
+)
+
+/* ----------------------------------------------------------------
+ *							T y p e s
+ *-----------------------------------------------------------------*/
+
+type RotateInterval int
+
+// RotationOptions describes when a log file is rotated and how many
+// rotated generations are kept. The zero value disables rotation.
+type RotationOptions struct {
+	MaxSize    int64          // rotate when the file exceeds this size (bytes), 0 is unlimited
+	Interval   RotateInterval // scheduled rollover
+	MaxBackups int            // rotated files to keep, 0 keeps them all
+	Compress   bool           // gzip the rotated files
+}
+
+// RotatingWriter is an io.WriteCloser over a log file which rotates
+// according to its RotationOptions.
+type RotatingWriter struct {
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+	bgMu     sync.Mutex // serializes compression & pruning
+	filename string
+	opts     RotationOptions
+	file     *os.File // nil after a failed rotation, reopened on write
+	size     int64
+	rollover time.Time
+	closed   bool
+}
+
+/* ----------------------------------------------------------------
+ *							C o n s t r u c t o r s
+ *-----------------------------------------------------------------*/
+
+// NewRotatingWriter opens filename for writing, either truncating it
+// or appending to it, and rotates it according to opts.
+func NewRotatingWriter(filename string, opts RotationOptions, truncate bool) (*RotatingWriter, error) {
+	rw := &RotatingWriter{filename: filename, opts: opts}
+	if err := rw.open(truncate); err != nil {
+		return nil, err
+	}
+	return rw, nil
+}
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// Enabled is true if the options call for any kind of rotation.
+func (o RotationOptions) Enabled() bool {
+	return o.MaxSize > 0 || o.Interval != RotateNever
+}
+
+// implements io.Writer. The file is rotated before writing p if it
+// is due or p would exceed the maximum size.
+func (rw *RotatingWriter) Write(p []byte) (int, error) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	if rw.closed {
+		return 0, os.ErrClosed
+	}
+
+	if rw.file == nil {
+		if err := rw.open(false); err != nil {
+			return 0, err
+		}
+	} else if rw.due(int64(len(p))) {
+		// a failed rotation keeps the current file, if it can
+		if err := rw.rotate(); err != nil && rw.file == nil {
+			return 0, err
+		}
+	}
+
+	n, err := rw.file.Write(p)
+	rw.size += int64(n)
+	return n, err
+}
+
+// Rotate forces the rotation of the current file.
+func (rw *RotatingWriter) Rotate() error {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	if rw.closed {
+		return os.ErrClosed
+	}
+	if rw.file == nil {
+		return rw.open(false)
+	}
+	return rw.rotate()
+}
+
+// implements io.Closer. It waits for pending compressions.
+func (rw *RotatingWriter) Close() error {
+	rw.mu.Lock()
+	var err error = nil
+	rw.closed = true
+	if rw.file != nil {
+		err = rw.file.Close()
+		rw.file = nil
+	}
+	rw.mu.Unlock()
+
+	rw.wg.Wait()
+	return err
+}
+
+// whether writing n more bytes calls for a rotation
+func (rw *RotatingWriter) due(n int64) bool {
+	if rw.opts.MaxSize > 0 && rw.size > 0 && rw.size+n > rw.opts.MaxSize {
+		return true
+	}
+	return !rw.rollover.IsZero() && !time.Now().Before(rw.rollover)
+}
+
+// opens the current log file
+func (rw *RotatingWriter) open(truncate bool) error {
+	fileFlags := os.O_CREATE | os.O_WRONLY
+	if truncate {
+		fileFlags |= os.O_TRUNC
+	} else {
+		fileFlags |= os.O_APPEND
+	}
+
+	fd, err := os.OpenFile(rw.filename, fileFlags, 0666)
+	if err != nil {
+		return err
+	}
+
+	rw.file = fd
+	rw.size = 0
+	if fi, err := fd.Stat(); err == nil {
+		rw.size = fi.Size()
+	}
+	rw.rollover = nextRollover(time.Now(), rw.opts.Interval)
+	return nil
+}
+
+// renames the current file to its timestamped backup name and opens
+// a new one. Compression and pruning happen in the background. If the
+// rename fails the current file is reopened and the next write tries
+// again; if no file could be opened the next write reopens it.
+func (rw *RotatingWriter) rotate() error {
+	err := rw.file.Close()
+	rw.file = nil
+	if err != nil {
+		return err
+	}
+
+	// never overwrite a backup made within the same millisecond
+	t := time.Now()
+	backup := rw.backupName(t)
+	for fileExists(backup) || fileExists(backup+".gz") {
+		t = t.Add(time.Millisecond)
+		backup = rw.backupName(t)
+	}
+	if err := os.Rename(rw.filename, backup); err != nil {
+		rw.open(false)
+		return err
+	}
+	if err := rw.open(true); err != nil {
+		return err
+	}
+	rw.writeLeader()
+
+	rw.wg.Add(1)
+	go func() {
+		defer rw.wg.Done()
+		rw.bgMu.Lock()
+		defer rw.bgMu.Unlock()
+		if rw.opts.Compress {
+			// it may have been pruned already
+			if err := gzipFile(backup); err != nil && !os.IsNotExist(err) {
+				fmt.Fprintf(os.Stderr, "mlog: compressing %s: %v\n", backup, err)
+			}
+		}
+		rw.prune()
+	}()
+	return nil
+}
+
+// starts the file with the BOM and the [BEG] marker, like openLogFile()
+// does, without checking whether the rotation is due.
+func (rw *RotatingWriter) writeLeader() {
+	n, _ := io.WriteString(rw.file, string(UTF8_BOM)+fileLeader)
+	rw.size += int64(n)
+}
+
+// ends the file with the [END] marker, like closeLogFile() does,
+// without checking whether the rotation is due.
+func (rw *RotatingWriter) writeTrailer() {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	if rw.file != nil {
+		n, _ := io.WriteString(rw.file, fileTrailer)
+		rw.size += int64(n)
+	}
+}
+
+// app.log => app-20250102T150405.000.log
+func (rw *RotatingWriter) backupName(t time.Time) string {
+	ext := filepath.Ext(rw.filename)
+	base := strings.TrimSuffix(rw.filename, ext)
+	return base + "-" + t.Format(backupTimeFormat) + ext
+}
+
+// removes the oldest rotated files beyond MaxBackups
+func (rw *RotatingWriter) prune() {
+	if rw.opts.MaxBackups <= 0 {
+		return
+	}
+
+	// listed rather than globbed, the name may contain [ * or ?
+	dir, file := filepath.Split(rw.filename)
+	entries, err := os.ReadDir(filepath.Clean(dir + "."))
+	if err != nil {
+		return
+	}
+
+	// only our own backups, not other files sharing the base name
+	ext := filepath.Ext(file)
+	base := strings.TrimSuffix(file, ext) + "-"
+	matches := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, base) {
+			continue
+		}
+		stamp := strings.TrimPrefix(name, base)
+		stamp = strings.TrimSuffix(strings.TrimSuffix(stamp, ".gz"), ext)
+		if _, err := time.Parse(backupTimeFormat, stamp); err == nil {
+			matches = append(matches, filepath.Join(dir, name))
+		}
+	}
+
+	// the timestamp makes the lexical order chronological
+	sort.Strings(matches)
+	for len(matches) > rw.opts.MaxBackups {
+		os.Remove(matches[0])
+		matches = matches[1:]
+	}
+}
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// the time of the next scheduled rollover after t, or the zero time
+// for unscheduled rotation.
+func nextRollover(t time.Time, interval RotateInterval) time.Time {
+	switch interval {
+	case RotateHourly:
+		// on the local clock, zones may be offset by a fraction of an hour
+		y, m, d := t.Date()
+		return time.Date(y, m, d, t.Hour()+1, 0, 0, 0, t.Location())
+	case RotateDaily:
+		y, m, d := t.Date()
+		return time.Date(y, m, d+1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Time{}
+	}
+}
+
+// whether the file exists
+func fileExists(filename string) bool {
+	_, err := os.Stat(filename)
+	return err == nil
+}
+
+// compresses filename into filename.gz and removes the original
+func gzipFile(filename string) error {
+	src, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(filename + ".gz")
+	if err != nil {
+		return err
+	}
+
+	zw := gzip.NewWriter(dst)
+	zw.Name = filepath.Base(filename)
+	if _, err = io.Copy(zw, src); err == nil {
+		err = zw.Close()
+	}
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(filename + ".gz")
+		return err
+	}
+
+	src.Close()
+	return os.Remove(filename)
+}
+
+// ParseRotation parses a rotation spec with comma-separated items:
+// size=N[K|M|G], hourly, daily, keep=N and gzip.
+func ParseRotation(spec string) (RotationOptions, error) {
+	var opts RotationOptions
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.ToLower(strings.TrimSpace(item))
+		key, value, _ := strings.Cut(item, "=")
+		switch key {
+		case "":
+			continue
+
+		case "size":
+			size, err := parseSize(value)
+			if err != nil {
+				return opts, fmt.Errorf("mlog: bad rotation size %q: %w", value, err)
+			}
+			opts.MaxSize = size
+
+		case "hourly":
+			opts.Interval = RotateHourly
+
+		case "daily":
+			opts.Interval = RotateDaily
+
+		case "keep":
+			keep, err := strconv.Atoi(value)
+			if err != nil || keep < 0 {
+				return opts, fmt.Errorf("mlog: bad rotation keep count %q", value)
+			}
+			opts.MaxBackups = keep
+
+		case "gzip":
+			opts.Compress = true
+
+		default:
+			return opts, fmt.Errorf("mlog: unknown rotation item %q", item)
+		}
+	}
+
+	return opts, nil
+}
+
+// parses a byte size with an optional K, M or G suffix
+func parseSize(s string) (int64, error) {
+	var unit int64 = 1
+	switch {
+	case strings.HasSuffix(s, "k"):
+		unit = 1 << 10
+	case strings.HasSuffix(s, "m"):
+		unit = 1 << 20
+	case strings.HasSuffix(s, "g"):
+		unit = 1 << 30
+	}
+	if unit != 1 {
+		s = s[:len(s)-1]
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("negative size")
+	}
+	return n * unit, nil
+}
@@ -0,0 +1,23 @@
+//go:build !unix && !windows
+
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Signal handling for the other platforms (js, wasip1...): there are
+ * no SIGUSR1, SIGUSR2 or SIGHUP so it does nothing.
+ *-----------------------------------------------------------------*/
+package mlog
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// HandleSignals does nothing on this platform.
+func HandleSignals() {
+}
+
+// StopSignals does nothing on this platform.
+func StopSignals() {
+}
@@ -0,0 +1,98 @@
+//go:build unix
+
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Unix-specific runtime control through signals: SIGUSR1 and SIGUSR2
+ * lower and raise the level of the default logger, SIGHUP reopens its
+ * log file after an external logrotate.
+ *-----------------------------------------------------------------*/
+package mlog
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+/* ----------------------------------------------------------------
+ *							G l o b a l s
+ *-----------------------------------------------------------------*/
+
+var (
+	signalMu   sync.Mutex
+	signalCh   chan os.Signal = nil // nil when not handling signals
+	signalDone chan struct{}  = nil
+)
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// HandleSignals starts handling SIGUSR1 (one level more verbose, down
+// to Trace), SIGUSR2 (one level less verbose) and SIGHUP (reopen the
+// log file) for the default logger. Every change is logged.
+//
+//	kill -USR1 <pid>
+func HandleSignals() {
+	signalMu.Lock()
+	defer signalMu.Unlock()
+
+	if signalCh != nil {
+		return
+	}
+
+	signalCh = make(chan os.Signal, 4)
+	signalDone = make(chan struct{})
+	signal.Notify(signalCh, syscall.SIGUSR1, syscall.SIGUSR2, syscall.SIGHUP)
+	go handleSignals(std, signalCh, signalDone)
+}
+
+// StopSignals stops the handling started by HandleSignals().
+func StopSignals() {
+	signalMu.Lock()
+	defer signalMu.Unlock()
+
+	if signalCh == nil {
+		return
+	}
+
+	signal.Stop(signalCh)
+	close(signalDone)
+	signalCh = nil
+	signalDone = nil
+}
+
+// applies the signals to the logger until done is closed
+func handleSignals(l *Logger, ch <-chan os.Signal, done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+
+		case sig := <-ch:
+			var message string
+			switch sig {
+			case syscall.SIGUSR1:
+				from, to := l.stepLevel(-1)
+				message = fmt.Sprintf("mlog: SIGUSR1 changed the level from %s to %s", levelName(from), levelName(to))
+
+			case syscall.SIGUSR2:
+				from, to := l.stepLevel(1)
+				message = fmt.Sprintf("mlog: SIGUSR2 changed the level from %s to %s", levelName(from), levelName(to))
+
+			case syscall.SIGHUP:
+				if err := l.Reopen(); err != nil {
+					message = fmt.Sprintf("mlog: SIGHUP could not reopen the log file: %v", err)
+				} else {
+					message = "mlog: SIGHUP reopened the log file"
+				}
+			}
+			l.dispatch(newRecord(LevelWarning, message))
+		}
+	}
+}
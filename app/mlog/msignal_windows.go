@@ -0,0 +1,21 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Windoze-specific code for the signal handling: there are no SIGUSR1,
+ * SIGUSR2 or SIGHUP so it does nothing.
+ *-----------------------------------------------------------------*/
+package mlog
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// HandleSignals does nothing on Windows.
+func HandleSignals() {
+}
+
+// StopSignals does nothing on Windows.
+func StopSignals() {
+}
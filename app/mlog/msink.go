@@ -0,0 +1,321 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * MLog output sinks. Besides its main output a logger can fan out
+ * every entry to additional writers, each with its own minimum level
+ * and timestamp format. For example errors to a file while showing
+ * everything down to Debug on stderr.
+ *-----------------------------------------------------------------*/
+package mlog
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"time"
+)
+
+/* ----------------------------------------------------------------
+ *							G l o b a l s
+ *-----------------------------------------------------------------*/
+
+const (
+	// the default sink timestamp, same as the default stderr output
+	defaultSinkTimeFormat string = "2006-01-02 15:04:05"
+
+	// the sink level of a logger without sinks
+	noSinkLevel LogLevel = math.MaxInt32
+)
+
+/* ----------------------------------------------------------------
+ *							T y p e s
+ *-----------------------------------------------------------------*/
+
+// Sink is an additional output of a logger. It receives every entry
+// whose level is at least its minimum level, regardless of the level
+// of the logger itself.
+type Sink struct {
+	mu         sync.Mutex
+	minLevel   LogLevel
+	w          io.Writer
+	timeFormat string
+	forward    func(*record) // record-level sinks instead of w
+	caller     bool          // forward or layout wants the call site
+	layout     *Layout       // nil for the default line
+	encoder    Encoder       // EncodeHuman for the default line or layout
+	closer     io.Closer     // connection of its own, if any
+	unlocked   bool          // forward is safe for concurrent use
+	mainOnly   bool          // gets what the main output gets instead
+}
+
+// Record is a log entry as handed to the function of AddRecordSink().
+type Record struct {
+	Time     time.Time
+	Level    LogLevel
+	Message  string
+	Tags     []ILogKeyValuePair // bound tags followed by the caller's
+	Rendered []RenderedTag      // the Tags as written (redacted)
+	Caller   *CallerInfo        // the call site, nil if unknown
+}
+
+// RenderedTag is a tag as written, its quoted value unquoted. The key
+// is empty for tags written without one.
+type RenderedTag struct {
+	Key   string
+	Value string
+}
+
+// SinkOption customizes a sink created with AddSink()
+type SinkOption func(*Sink)
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// Level returns the minimum level of the sink.
+func (s *Sink) Level() LogLevel {
+	return s.minLevel
+}
+
+// implements fmt.Stringer with the kind of output: the type of its
+// writer, or "forward" for the record-level sinks (syslog, network...)
+func (s *Sink) String() string {
+	if s.forward != nil {
+		return "forward"
+	}
+	if cw, ok := s.w.(*customLogWriter); ok {
+		return fmt.Sprintf("%T", cw.writer)
+	}
+	return fmt.Sprintf("%T", s.w)
+}
+
+// Close releases the connection the sink made on its own (syslog,
+// journal...), later entries are dropped. Sinks writing to a writer of
+// yours leave it open. Logger.Close() closes the sinks too.
+func (s *Sink) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+// writes a record (already known to meet the sink's level)
+func (s *Sink) write(prefix string, r *record) {
+	if s.unlocked {
+		s.forward(r)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.forward != nil {
+		s.forward(r)
+		return
+	}
+
+	if s.encoder != EncodeHuman {
+		s.w.Write(append(s.encoder.encode(prefix, r), '\n'))
+		return
+	}
+
+	if s.layout != nil {
+		s.w.Write([]byte(terminated(s.layout.format(prefix, r, nil))))
+		return
+	}
+
+	line := []byte(terminated(prefix + r.line))
+	if cw, ok := s.w.(*customLogWriter); ok {
+		cw.writeAt(r.time, line)
+	} else {
+		s.w.Write(line)
+	}
+}
+
+// AddSink adds an output writer receiving every entry at minLevel or
+// above. By default each line is timestamped like the stderr output,
+// use WithTimeFormat() to change it, WithLayout() to rearrange it or
+// WithEncoder() for logfmt or JSON lines.
+func (l *Logger) AddSink(w io.Writer, minLevel LogLevel, opts ...SinkOption) *Sink {
+	s := &Sink{minLevel: minLevel, w: w, timeFormat: defaultSinkTimeFormat}
+	for _, opt := range opts {
+		opt(s)
+	}
+	switch {
+	case s.encoder != EncodeHuman:
+		s.caller = true
+	case s.layout != nil:
+		s.caller = s.layout.caller
+	case s.timeFormat != "":
+		s.w = newCustomLogWriter(w, s.timeFormat)
+	}
+
+	l.addSink(s)
+	return s
+}
+
+// AddRecordSink calls fn with every entry at minLevel or above, along
+// with its call site. fn is never called concurrently for the sink and
+// must not log to the same logger.
+func (l *Logger) AddRecordSink(fn func(Record), minLevel LogLevel) *Sink {
+	s := &Sink{minLevel: minLevel, caller: true}
+	s.forward = recordForwarder(fn)
+
+	l.addSink(s)
+	return s
+}
+
+// AddMainRecordSink is AddRecordSink() for exactly the entries written
+// to the main output, i.e. at the level and per-package overrides of
+// the logger. Unlike other sinks it enables no entry by itself.
+func (l *Logger) AddMainRecordSink(fn func(Record)) *Sink {
+	s := &Sink{minLevel: noSinkLevel, caller: true, mainOnly: true}
+	s.forward = recordForwarder(fn)
+
+	l.addSink(s)
+	return s
+}
+
+// Sinks returns the sinks of the logger in the order they were added.
+func (l *Logger) Sinks() []*Sink {
+	if current := l.sinks.Load(); current != nil {
+		return append([]*Sink(nil), *current...)
+	}
+	return nil
+}
+
+// attaches a fully configured sink
+func (l *Logger) addSink(s *Sink) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var sinks []*Sink
+	if current := l.sinks.Load(); current != nil {
+		sinks = append(sinks, *current...)
+	}
+	sinks = append(sinks, s)
+	l.setSinks(sinks)
+}
+
+// RemoveSink detaches the sink from the logger. It returns false if
+// the sink did not belong to it.
+func (l *Logger) RemoveSink(s *Sink) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	current := l.sinks.Load()
+	if current == nil {
+		return false
+	}
+
+	sinks := make([]*Sink, 0, len(*current))
+	for _, other := range *current {
+		if other != s {
+			sinks = append(sinks, other)
+		}
+	}
+	if len(sinks) == len(*current) {
+		return false
+	}
+
+	l.setSinks(sinks)
+	return true
+}
+
+// publishes a new sink list (copy on write) and the lowest sink level.
+// Must be called with the configuration lock held.
+func (c *loggerCore) setSinks(sinks []*Sink) {
+	minLevel := noSinkLevel
+	for _, s := range sinks {
+		if s.minLevel < minLevel {
+			minLevel = s.minLevel
+		}
+	}
+
+	if len(sinks) == 0 {
+		c.sinks.Store(nil)
+	} else {
+		c.sinks.Store(&sinks)
+	}
+	c.sinkLevel.Store(int32(minLevel))
+	c.updateCaller()
+}
+
+// tells output() whether to capture the call site, which the layout
+// or a sink may want. Must be called with the configuration lock held.
+func (c *loggerCore) updateCaller() {
+	caller := c.layout != nil && c.layout.caller
+	if sinks := c.sinks.Load(); sinks != nil {
+		for _, s := range *sinks {
+			caller = caller || s.caller
+		}
+	}
+	c.caller.Store(caller)
+}
+
+// writes the record to every sink whose level it meets
+func (c *loggerCore) writeSinks(prefix string, r *record) {
+	if sinks := c.sinks.Load(); sinks != nil {
+		for _, s := range *sinks {
+			if r.level >= s.minLevel || s.mainOnly && r.main {
+				s.write(prefix, r)
+			}
+		}
+	}
+}
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// WithTimeFormat sets the timestamp layout (see time.Layout) that
+// prefixes every line of the sink. An empty layout disables it.
+func WithTimeFormat(layout string) SinkOption {
+	return func(s *Sink) {
+		s.timeFormat = layout
+	}
+}
+
+// AddSink adds an output writer to the default logger. See
+// Logger.AddSink().
+func AddSink(w io.Writer, minLevel LogLevel, opts ...SinkOption) *Sink {
+	return std.AddSink(w, minLevel, opts...)
+}
+
+// AddRecordSink calls fn with the entries of the default logger. See
+// Logger.AddRecordSink().
+func AddRecordSink(fn func(Record), minLevel LogLevel) *Sink {
+	return std.AddRecordSink(fn, minLevel)
+}
+
+// RemoveSink detaches the sink from the default logger.
+func RemoveSink(s *Sink) bool {
+	return std.RemoveSink(s)
+}
+
+// hands the records to fn as a Record
+func recordForwarder(fn func(Record)) func(*record) {
+	return func(r *record) {
+		rec := Record{Time: r.time, Level: r.level, Message: r.message, Tags: r.tags}
+		rec.Rendered = make([]RenderedTag, len(r.tagText))
+		for i, rendered := range r.tagText {
+			key, value, _ := splitRendered(rendered)
+			rec.Rendered[i] = RenderedTag{key, value}
+		}
+		if r.pc != 0 {
+			rec.Caller = CallerInfoAt(r.pc)
+		}
+		fn(rec)
+	}
+}
+
+// ensures the line ends with a newline
+func terminated(line string) string {
+	if len(line) == 0 || line[len(line)-1] != '\n' {
+		line += "\n"
+	}
+	return line
+}
@@ -0,0 +1,254 @@
+//go:build go1.21
+// +build go1.21
+
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Bridge between log/slog and MLog (Go 1.21+). SlogHandler turns slog
+ * records into mlog entries so that third-party libraries logging
+ * through slog go through the mlog levels and files. The other way
+ * around, a slog sink forwards mlog entries to any slog.Handler.
+ *-----------------------------------------------------------------*/
+package mlog
+
+import (
+	"context"
+	"log/slog"
+)
+
+/* ----------------------------------------------------------------
+ *							G l o b a l s
+ *-----------------------------------------------------------------*/
+
+var _ slog.Handler = (*SlogHandler)(nil)
+
+/* ----------------------------------------------------------------
+ *							T y p e s
+ *-----------------------------------------------------------------*/
+
+// SlogHandler is a slog.Handler that writes through an mlog Logger.
+// Attributes become mlog tags, those inside groups get dotted keys.
+type SlogHandler struct {
+	l     *Logger
+	attrs []ILogKeyValuePair
+	group string // prefix of the keys, i.e. "request."
+}
+
+/* ----------------------------------------------------------------
+ *							C o n s t r u c t o r s
+ *-----------------------------------------------------------------*/
+
+// NewSlogHandler creates a slog handler writing to the logger, or to
+// the default logger if nil. To route all slog output through mlog:
+//
+//	slog.SetDefault(slog.New(mlog.NewSlogHandler(nil)))
+func NewSlogHandler(l *Logger) *SlogHandler {
+	if l == nil {
+		l = std
+	}
+	return &SlogHandler{l: l}
+}
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// implements slog.Handler. It is true if the logger, any per-package
+// override or any sink may take the level.
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	lvl := LevelFromSlog(level)
+	if lvl < buildLevel {
+		return false
+	}
+
+	lowest := h.l.Level()
+	if spec := h.l.spec.Load(); spec != nil && spec.lowest() < lowest {
+		lowest = spec.lowest()
+	}
+	if sinkLevel := LogLevel(h.l.sinkLevel.Load()); sinkLevel < lowest {
+		lowest = sinkLevel
+	}
+	return lowest <= lvl
+}
+
+// implements slog.Handler. Per-package overrides apply to the package
+// of the slog call site. Fatal entries do not terminate the application.
+func (h *SlogHandler) Handle(_ context.Context, r slog.Record) error {
+	level := LevelFromSlog(r.Level)
+	if level < buildLevel {
+		return nil
+	}
+
+	threshold := h.l.Level()
+	if spec := h.l.spec.Load(); spec != nil && r.PC != 0 {
+		if override := spec.levelForPC(r.PC); override != noOverride {
+			threshold = override
+		}
+	}
+	main := threshold <= level
+	if !main && LogLevel(h.l.sinkLevel.Load()) > level {
+		return nil
+	}
+
+	tags := make([]ILogKeyValuePair, 0, len(h.attrs)+r.NumAttrs())
+	tags = append(tags, h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		tags = appendSlogAttr(tags, h.group, a)
+		return true
+	})
+
+	t := r.Time
+	if t.IsZero() {
+		t = h.l.now()
+	}
+	h.l.outputAt(t, r.PC, level, main, r.Message, tags)
+	return nil
+}
+
+// implements slog.Handler
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	tags := make([]ILogKeyValuePair, 0, len(h.attrs)+len(attrs))
+	tags = append(tags, h.attrs...)
+	for _, a := range attrs {
+		tags = appendSlogAttr(tags, h.group, a)
+	}
+	return &SlogHandler{l: h.l, attrs: tags, group: h.group}
+}
+
+// implements slog.Handler
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &SlogHandler{l: h.l, attrs: h.attrs, group: h.group + name + "."}
+}
+
+// AddSlogSink forwards every entry at minLevel or above to a slog
+// handler, tags become string attributes (as rendered, redacted) and
+// the call site is the record's PC. Do not forward to a SlogHandler
+// writing to the same logger.
+func (l *Logger) AddSlogSink(h slog.Handler, minLevel LogLevel) *Sink {
+	s := &Sink{minLevel: minLevel, caller: true}
+	s.forward = func(r *record) {
+		ctx := context.Background()
+		level := SlogLevel(r.level)
+		if !h.Enabled(ctx, level) {
+			return
+		}
+
+		sr := slog.NewRecord(r.time, level, r.message, r.pc)
+		for _, rendered := range r.tagText {
+			sr.AddAttrs(slogAttrOf(rendered))
+		}
+		h.Handle(ctx, sr)
+	}
+
+	l.addSink(s)
+	return s
+}
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// AddSlogSink forwards the entries of the default logger to a slog
+// handler. See Logger.AddSlogSink().
+func AddSlogSink(h slog.Handler, minLevel LogLevel) *Sink {
+	return std.AddSlogSink(h, minLevel)
+}
+
+// SlogLevel maps an mlog level to its slog equivalent. Trace and Fatal
+// lie beyond slog's Debug and Error.
+func SlogLevel(level LogLevel) slog.Level {
+	switch level {
+	case LevelTrace:
+		return slog.LevelDebug - 4
+	case LevelDebug:
+		return slog.LevelDebug
+	case LevelInfo:
+		return slog.LevelInfo
+	case LevelWarning:
+		return slog.LevelWarn
+	case LevelError:
+		return slog.LevelError
+	default:
+		return slog.LevelError + 4
+	}
+}
+
+// LevelFromSlog maps a slog level to the mlog level whose range
+// contains it, i.e. anything below slog.LevelDebug is Trace.
+func LevelFromSlog(level slog.Level) LogLevel {
+	switch {
+	case level < slog.LevelDebug:
+		return LevelTrace
+	case level < slog.LevelInfo:
+		return LevelDebug
+	case level < slog.LevelWarn:
+		return LevelInfo
+	case level < slog.LevelError:
+		return LevelWarning
+	case level < slog.LevelError+4:
+		return LevelError
+	default:
+		return LevelFatal
+	}
+}
+
+// converts a slog attribute (groups flattened) into mlog tags
+func appendSlogAttr(tags []ILogKeyValuePair, group string, a slog.Attr) []ILogKeyValuePair {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return tags
+	}
+
+	key := group + a.Key
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		prefix := group
+		if a.Key != "" {
+			prefix = key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			tags = appendSlogAttr(tags, prefix, ga)
+		}
+
+	case slog.KindString:
+		tags = append(tags, String(key, a.Value.String()))
+
+	case slog.KindInt64:
+		tags = append(tags, Int64(key, a.Value.Int64()))
+
+	case slog.KindUint64:
+		tags = append(tags, Uint64(key, a.Value.Uint64()))
+
+	case slog.KindFloat64:
+		tags = append(tags, Float64(key, a.Value.Float64()))
+
+	case slog.KindBool:
+		tags = append(tags, Bool(key, a.Value.Bool()))
+
+	case slog.KindDuration:
+		tags = append(tags, Duration(key, a.Value.Duration()))
+
+	case slog.KindTime:
+		tags = append(tags, Time(key, a.Value.Time()))
+
+	default:
+		tags = append(tags, String(key, a.Value.String()))
+	}
+
+	return tags
+}
+
+// converts a rendered mlog tag into a string attribute by splitting
+// its key=value text.
+func slogAttrOf(rendered string) slog.Attr {
+	key, value, found := splitRendered(rendered)
+	if !found {
+		return slog.String("tag", value)
+	}
+	return slog.String(key, value)
+}
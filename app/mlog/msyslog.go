@@ -0,0 +1,400 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Sinks for the system logging daemons: RFC 5424 syslog and the
+ * systemd journal (native protocol), both over their Unix datagram
+ * sockets. The levels map onto syslog priorities and the tags become
+ * structured data or journal fields.
+ *-----------------------------------------------------------------*/
+package mlog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+/* ----------------------------------------------------------------
+ *							G l o b a l s
+ *-----------------------------------------------------------------*/
+
+const (
+	// RFC 5424 SD-ID of the tags (32473 is the enterprise number
+	// reserved for documentation by RFC 5612)
+	syslogSDID string = "mlog@32473"
+	// RFC 5424 timestamp
+	syslogTimeFormat string = "2006-01-02T15:04:05.000000Z07:00"
+
+	// the socket of the journal's native protocol
+	defaultJournalSocket string = "/run/systemd/journal/socket"
+)
+
+// Syslog facility enumeration (the usual ones)
+const (
+	FacilityUser     SyslogFacility = 1
+	FacilityDaemon   SyslogFacility = 3
+	FacilityAuthPriv SyslogFacility = 10
+	FacilityLocal0   SyslogFacility = 16
+	FacilityLocal1   SyslogFacility = 17
+	FacilityLocal2   SyslogFacility = 18
+	FacilityLocal3   SyslogFacility = 19
+	FacilityLocal4   SyslogFacility = 20
+	FacilityLocal5   SyslogFacility = 21
+	FacilityLocal6   SyslogFacility = 22
+	FacilityLocal7   SyslogFacility = 23
+)
+
+var (
+	// where the syslog daemon listens on Linux, macOS and the BSDs
+	syslogSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+	// the journal fields of a special meaning (see systemd.journal-fields),
+	// tags by those names get a TAG_ prefix
+	journalReserved = map[string]bool{
+		"MESSAGE": true, "MESSAGE_ID": true, "PRIORITY": true,
+		"CODE_FILE": true, "CODE_LINE": true, "CODE_FUNC": true,
+		"ERRNO": true, "INVOCATION_ID": true, "USER_INVOCATION_ID": true,
+		"SYSLOG_FACILITY": true, "SYSLOG_IDENTIFIER": true, "SYSLOG_PID": true,
+		"SYSLOG_TIMESTAMP": true, "SYSLOG_RAW": true, "DOCUMENTATION": true,
+		"TID": true, "UNIT": true, "USER_UNIT": true,
+	}
+)
+
+/* ----------------------------------------------------------------
+ *							T y p e s
+ *-----------------------------------------------------------------*/
+
+// SyslogFacility is the kind of program logging to syslog
+type SyslogFacility int
+
+// SyslogOptions tell where and how a syslog sink sends its entries.
+type SyslogOptions struct {
+	Address  string         // Unix datagram socket, "" for /dev/log or the platform's
+	Facility SyslogFacility // 0 (kernel) stands for FacilityUser
+	AppName  string         // "" for the program name
+	Hostname string         // "" for os.Hostname()
+}
+
+// JournalOptions tell where and how a journal sink sends its entries.
+type JournalOptions struct {
+	Address    string // Unix datagram socket, "" for /run/systemd/journal/socket
+	Identifier string // SYSLOG_IDENTIFIER, "" for the program name
+}
+
+// a datagram connection to a local daemon. It is dialed again once
+// after a failed write since the daemon may have restarted.
+type datagramConn struct {
+	mu     sync.Mutex
+	addr   string
+	conn   *net.UnixConn
+	closed bool
+}
+
+/* ----------------------------------------------------------------
+ *							C o n s t r u c t o r s
+ *-----------------------------------------------------------------*/
+
+// connects to the daemon's socket
+func dialDatagram(addr string) (*datagramConn, error) {
+	d := &datagramConn{addr: addr}
+	if err := d.dial(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// connects to the socket. Must be called with the lock held.
+func (d *datagramConn) dial() error {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: d.addr, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	d.conn = conn
+	return nil
+}
+
+// sends a datagram
+func (d *datagramConn) send(b []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return net.ErrClosed
+	}
+	for attempt := 0; ; attempt++ {
+		if d.conn == nil {
+			if err := d.dial(); err != nil {
+				return err
+			}
+		}
+		_, err := d.conn.Write(b)
+		if err == nil || attempt > 0 {
+			return err
+		}
+		d.conn.Close()
+		d.conn = nil
+	}
+}
+
+// implements io.Closer
+func (d *datagramConn) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.closed = true
+	if d.conn == nil {
+		return nil
+	}
+	err := d.conn.Close()
+	d.conn = nil
+	return err
+}
+
+// AddSyslogSink sends every entry at minLevel or above to the syslog
+// daemon as an RFC 5424 message. The tags go in the structured data
+// element [mlog@32473 Key="value"...]. Entries that cannot be sent are
+// dropped.
+func (l *Logger) AddSyslogSink(opts SyslogOptions, minLevel LogLevel) (*Sink, error) {
+	var conn *datagramConn
+	var err error
+	if opts.Address != "" {
+		conn, err = dialDatagram(opts.Address)
+	} else {
+		for _, addr := range syslogSockets {
+			if conn, err = dialDatagram(addr); err == nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("mlog: no syslog daemon: %w", err)
+	}
+
+	if opts.Facility == 0 {
+		opts.Facility = FacilityUser
+	}
+	if opts.AppName == "" {
+		opts.AppName = programName()
+	}
+	if opts.Hostname == "" {
+		opts.Hostname, _ = os.Hostname()
+	}
+	opts.AppName = syslogHeaderField(opts.AppName, 48)
+	opts.Hostname = syslogHeaderField(opts.Hostname, 255)
+	pid := os.Getpid()
+
+	s := &Sink{minLevel: minLevel, closer: conn}
+	s.forward = func(r *record) {
+		conn.send(syslogMessage(&opts, pid, r))
+	}
+
+	l.addSink(s)
+	return s, nil
+}
+
+// AddJournalSink sends every entry at minLevel or above to the systemd
+// journal. The tags become fields named after their keys (uppercased,
+// i.e. USER for User) and the call site goes in CODE_FILE, CODE_LINE
+// and CODE_FUNC. Entries that cannot be sent are dropped.
+func (l *Logger) AddJournalSink(opts JournalOptions, minLevel LogLevel) (*Sink, error) {
+	if opts.Address == "" {
+		opts.Address = defaultJournalSocket
+	}
+	if opts.Identifier == "" {
+		opts.Identifier = programName()
+	}
+
+	conn, err := dialDatagram(opts.Address)
+	if err != nil {
+		return nil, fmt.Errorf("mlog: no journal: %w", err)
+	}
+
+	s := &Sink{minLevel: minLevel, closer: conn, caller: true}
+	s.forward = func(r *record) {
+		payload := journalPayload(opts.Identifier, r)
+		if err := conn.send(payload); err != nil && isMessageTooBig(err) {
+			conn.sendFile(payload)
+		}
+	}
+
+	l.addSink(s)
+	return s, nil
+}
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// AddSyslogSink sends the entries of the default logger to syslog. See
+// Logger.AddSyslogSink().
+func AddSyslogSink(opts SyslogOptions, minLevel LogLevel) (*Sink, error) {
+	return std.AddSyslogSink(opts, minLevel)
+}
+
+// AddJournalSink sends the entries of the default logger to the
+// systemd journal. See Logger.AddJournalSink().
+func AddJournalSink(opts JournalOptions, minLevel LogLevel) (*Sink, error) {
+	return std.AddJournalSink(opts, minLevel)
+}
+
+// the syslog severity of a level: debug (7), info (6), warning (4),
+// err (3) and crit (2)
+func syslogSeverity(level LogLevel) int {
+	switch level {
+	case LevelTrace, LevelDebug:
+		return 7
+	case LevelInfo:
+		return 6
+	case LevelWarning:
+		return 4
+	case LevelError:
+		return 3
+	default:
+		return 2
+	}
+}
+
+// the RFC 5424 message of a record
+func syslogMessage(opts *SyslogOptions, pid int, r *record) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "<%d>1 %s %s %s %d - ",
+		int(opts.Facility)*8+syslogSeverity(r.level),
+		r.time.Format(syslogTimeFormat), opts.Hostname, opts.AppName, pid)
+
+	if len(r.tagText) == 0 {
+		b.WriteString("-")
+	} else {
+		b.WriteString("[" + syslogSDID)
+		for _, rendered := range r.tagText {
+			key, value, found := splitRendered(rendered)
+			if !found {
+				key = "tag"
+			}
+			b.WriteString(" " + syslogParamName(key) + `="`)
+			for _, c := range value {
+				if c == '"' || c == '\\' || c == ']' {
+					b.WriteByte('\\')
+				}
+				b.WriteRune(c)
+			}
+			b.WriteString(`"`)
+		}
+		b.WriteString("]")
+	}
+
+	if r.message != "" {
+		b.WriteString(" " + r.message)
+	}
+	return b.Bytes()
+}
+
+// a header field of printable ASCII without spaces, "-" if empty
+func syslogHeaderField(s string, maxLen int) string {
+	field := strings.Map(func(c rune) rune {
+		if c <= ' ' || c > '~' {
+			return '_'
+		}
+		return c
+	}, s)
+	if len(field) > maxLen {
+		field = field[:maxLen]
+	}
+	if field == "" {
+		return "-"
+	}
+	return field
+}
+
+// an SD-NAME: up to 32 printable ASCII characters but = ] " and space
+func syslogParamName(key string) string {
+	name := strings.Map(func(c rune) rune {
+		if c <= ' ' || c > '~' || c == '=' || c == ']' || c == '"' {
+			return '_'
+		}
+		return c
+	}, key)
+	if len(name) > 32 {
+		name = name[:32]
+	}
+	if name == "" {
+		return "tag"
+	}
+	return name
+}
+
+// the journal's native protocol payload of a record
+func journalPayload(identifier string, r *record) []byte {
+	var b bytes.Buffer
+	field := func(name, value string) {
+		if strings.ContainsRune(value, '\n') {
+			// binary safe: name, little endian length, value
+			b.WriteString(name + "\n")
+			binary.Write(&b, binary.LittleEndian, uint64(len(value)))
+			b.WriteString(value + "\n")
+		} else {
+			b.WriteString(name + "=" + value + "\n")
+		}
+	}
+
+	field("MESSAGE", r.message)
+	field("PRIORITY", strconv.Itoa(syslogSeverity(r.level)))
+	field("SYSLOG_IDENTIFIER", identifier)
+	if r.pc != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.pc}).Next()
+		if frame.Function != "" {
+			field("CODE_FILE", frame.File)
+			field("CODE_LINE", strconv.Itoa(frame.Line))
+			field("CODE_FUNC", frame.Function)
+		}
+	}
+	for _, rendered := range r.tagText {
+		key, value, found := splitRendered(rendered)
+		if !found {
+			key = "tag"
+		}
+		field(journalFieldName(key), value)
+	}
+	return b.Bytes()
+}
+
+// a journal field name: uppercase letters, digits and underscores, not
+// starting with an underscore (reserved) or a digit, up to 64 long.
+// The names of the fields mlog or journald set are prefixed with TAG_.
+func journalFieldName(key string) string {
+	name := strings.Map(func(c rune) rune {
+		switch {
+		case c >= 'a' && c <= 'z':
+			return c - 'a' + 'A'
+		case c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+			return c
+		default:
+			return '_'
+		}
+	}, key)
+	name = strings.TrimLeft(name, "_")
+	if name == "" || name[0] >= '0' && name[0] <= '9' || journalReserved[name] {
+		name = "TAG_" + name
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+// the name of the running program
+func programName() string {
+	return filepath.Base(os.Args[0])
+}
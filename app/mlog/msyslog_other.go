@@ -0,0 +1,31 @@
+//go:build !unix && !windows
+
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Journal sink code for the other platforms (js, wasip1...): there is
+ * no journal so big entries are never handed over as a file.
+ *-----------------------------------------------------------------*/
+package mlog
+
+import "errors"
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// there is no journal on this platform
+func (d *datagramConn) sendFile(payload []byte) error {
+	return errors.New("mlog: no journal on this platform")
+}
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// there is no journal on this platform
+func isMessageTooBig(err error) bool {
+	return false
+}
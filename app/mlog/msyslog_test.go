@@ -0,0 +1,191 @@
+//go:build unix
+
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Tests of the syslog and journal sinks against a local Unix datagram
+ * socket: the RFC 5424 header and structured data, and the journal
+ * fields in both the plain and the binary (multi-line) form.
+ *-----------------------------------------------------------------*/
+package mlog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// a daemon's socket in the test's temporary directory
+func listenDatagram(t *testing.T) (*net.UnixConn, string) {
+	addr := filepath.Join(t.TempDir(), "log.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: addr, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("no Unix datagram socket: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn, addr
+}
+
+// the next datagram sent to the socket
+func receive(t *testing.T, conn *net.UnixConn) []byte {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 64*1024)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf[:n]
+}
+
+// a logger with a fixed clock
+func fixedLogger(at time.Time) *Logger {
+	l := NewLogger(io.Discard, "", LevelWarning)
+	l.SetClock(func() time.Time { return at })
+	return l
+}
+
+// the fields of a journal payload, checking that the values with
+// newlines are in the binary form
+func journalFields(t *testing.T, payload []byte) map[string]string {
+	t.Helper()
+	fields := make(map[string]string)
+	for len(payload) > 0 {
+		eol := bytes.IndexByte(payload, '\n')
+		if eol < 0 {
+			t.Fatalf("unterminated field %q", payload)
+		}
+		line := string(payload[:eol])
+		payload = payload[eol+1:]
+
+		if name, value, found := strings.Cut(line, "="); found {
+			if strings.ContainsRune(value, '\n') {
+				t.Errorf("%s has a newline in the plain form", name)
+			}
+			fields[name] = value
+			continue
+		}
+
+		if len(payload) < 8 {
+			t.Fatalf("%s: no length", line)
+		}
+		size := binary.LittleEndian.Uint64(payload)
+		payload = payload[8:]
+		if uint64(len(payload)) < size+1 || payload[size] != '\n' {
+			t.Fatalf("%s: bad length %d", line, size)
+		}
+		fields[line] = string(payload[:size])
+		payload = payload[size+1:]
+	}
+	return fields
+}
+
+func TestSyslogSink(t *testing.T) {
+	conn, addr := listenDatagram(t)
+	at := time.Date(2025, time.January, 2, 15, 4, 5, 123456000, time.UTC)
+	l := fixedLogger(at)
+	defer l.Close()
+	opts := SyslogOptions{Address: addr, Facility: FacilityLocal3, AppName: "my app", Hostname: "host"}
+	if _, err := l.AddSyslogSink(opts, LevelWarning); err != nil {
+		t.Fatal(err)
+	}
+
+	l.ErrorT("disk full", String("Path", `a"b\c]d`), String("a b", "x"), &kvRendered{"plain"})
+	header := fmt.Sprintf("<%d>1 2025-01-02T15:04:05.123456Z host my_app %d - ", 19*8+3, os.Getpid())
+	want := header + `[mlog@32473 Path="a\"b\\c\]d" a_b="x" tag="plain"] disk full`
+	if got := string(receive(t, conn)); got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+
+	l.Warn("no tags")
+	header = fmt.Sprintf("<%d>1 2025-01-02T15:04:05.123456Z host my_app %d - ", 19*8+4, os.Getpid())
+	if got := string(receive(t, conn)); got != header+"- no tags" {
+		t.Errorf("got  %q\nwant %q", got, header+"- no tags")
+	}
+}
+
+func TestSyslogSeverity(t *testing.T) {
+	tests := map[LogLevel]int{
+		LevelTrace: 7, LevelDebug: 7, LevelInfo: 6,
+		LevelWarning: 4, LevelError: 3, LevelFatal: 2,
+	}
+	for level, want := range tests {
+		if got := syslogSeverity(level); got != want {
+			t.Errorf("level %d: severity %d, want %d", level, got, want)
+		}
+	}
+}
+
+func TestJournalSink(t *testing.T) {
+	conn, addr := listenDatagram(t)
+	l := fixedLogger(time.Now())
+	defer l.Close()
+	if _, err := l.AddJournalSink(JournalOptions{Address: addr, Identifier: "myapp"}, LevelWarning); err != nil {
+		t.Fatal(err)
+	}
+
+	l.ErrorT("line one\nline two",
+		String("User", "john"),
+		String("Stack", "at a\nat b"),
+		String("Priority", "high"),
+		String("message", "shadow"),
+		String("9lives", "cat"),
+		String("_hidden", "no"),
+		&kvRendered{"plain"})
+	fields := journalFields(t, receive(t, conn))
+
+	want := map[string]string{
+		"MESSAGE":           "line one\nline two",
+		"PRIORITY":          "3",
+		"SYSLOG_IDENTIFIER": "myapp",
+		"USER":              "john",
+		"STACK":             "at a\nat b",
+		"TAG_PRIORITY":      "high",
+		"TAG_MESSAGE":       "shadow",
+		"TAG_9LIVES":        "cat",
+		"HIDDEN":            "no",
+		"TAG":               "plain",
+	}
+	for name, value := range want {
+		if fields[name] != value {
+			t.Errorf("%s=%q, want %q", name, fields[name], value)
+		}
+	}
+	if !strings.HasSuffix(fields["CODE_FUNC"], ".TestJournalSink") || filepath.Base(fields["CODE_FILE"]) != "msyslog_test.go" || fields["CODE_LINE"] == "" {
+		t.Errorf("call site %s %s:%s", fields["CODE_FUNC"], fields["CODE_FILE"], fields["CODE_LINE"])
+	}
+	if len(fields) != len(want)+3 {
+		t.Errorf("%d fields, want %d: %v", len(fields), len(want)+3, fields)
+	}
+}
+
+func TestJournalFieldName(t *testing.T) {
+	tests := map[string]string{
+		"User":                  "USER",
+		"request-id":            "REQUEST_ID",
+		"PRIORITY":              "TAG_PRIORITY",
+		"Message":               "TAG_MESSAGE",
+		"syslog_identifier":     "TAG_SYSLOG_IDENTIFIER",
+		"__CURSOR":              "CURSOR",
+		"2fa":                   "TAG_2FA",
+		strings.Repeat("k", 70): strings.Repeat("K", 64),
+	}
+	for key, want := range tests {
+		if got := journalFieldName(key); got != want {
+			t.Errorf("%q: %q, want %q", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+//go:build unix
+
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Unix-specific code of the journal sink: entries too big for a
+ * datagram are handed over as a file descriptor.
+ *-----------------------------------------------------------------*/
+package mlog
+
+import (
+	"errors"
+	"net"
+	"os"
+	"syscall"
+)
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// sends a payload too big for a datagram in an unlinked temporary
+// file, as sd_journal_sendv() does
+func (d *datagramConn) sendFile(payload []byte) error {
+	f, err := os.CreateTemp("/dev/shm", "mlog-journal-")
+	if err != nil {
+		if f, err = os.CreateTemp("", "mlog-journal-"); err != nil {
+			return err
+		}
+	}
+	defer f.Close()
+	os.Remove(f.Name())
+	if _, err := f.Write(payload); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return net.ErrClosed
+	}
+	if d.conn == nil {
+		if err := d.dial(); err != nil {
+			return err
+		}
+	}
+	// WriteMsgUnix() refuses connected datagram sockets
+	raw, err := d.conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+	rights := syscall.UnixRights(int(f.Fd()))
+	if ctrlErr := raw.Write(func(fd uintptr) bool {
+		err = syscall.Sendmsg(int(fd), nil, rights, nil, 0)
+		return err != syscall.EAGAIN
+	}); ctrlErr != nil {
+		return ctrlErr
+	}
+	return err
+}
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// whether a send failed because the datagram is too big
+func isMessageTooBig(err error) bool {
+	return errors.Is(err, syscall.EMSGSIZE) || errors.Is(err, syscall.ENOBUFS)
+}
@@ -0,0 +1,29 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Windoze-specific code of the journal sink: there is no journal so
+ * big entries are never handed over as a file.
+ *-----------------------------------------------------------------*/
+package mlog
+
+import "errors"
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// there is no journal on Windows
+func (d *datagramConn) sendFile(payload []byte) error {
+	return errors.New("mlog: no journal on Windows")
+}
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// there is no journal on Windows
+func isMessageTooBig(err error) bool {
+	return false
+}
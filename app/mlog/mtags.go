@@ -3,13 +3,19 @@
  *				  Copyright (C)2025 Lord of Scripts
  *							   goApp
  * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
- * MLog variadic tags: String, Rune, Int, Bool, YesNo, Byte & At.
+ * MLog variadic tags: String, Rune, Int, Bool, YesNo, Byte, At & Err as
+ * well as Int64, Uint64, Float64, Hex, Duration, Time, Strings, Ints
+ * and Any.
  * Tags like the log/slog package but enhanced.
  *-----------------------------------------------------------------*/
 package mlog
 
 import (
 	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
 	"unicode"
 )
 
@@ -31,6 +37,17 @@ var _ ILogKeyValuePair = (*kvYesNo)(nil)
 var _ ILogKeyValuePair = (*kvByte)(nil)
 var _ ILogKeyValuePair = (*kvAt)(nil)
 var _ ILogKeyValuePair = (*kvError)(nil)
+var _ ILogKeyValuePair = (*kvInt64)(nil)
+var _ ILogKeyValuePair = (*kvUint64)(nil)
+var _ ILogKeyValuePair = (*kvFloat64)(nil)
+var _ ILogKeyValuePair = (*kvHex)(nil)
+var _ ILogKeyValuePair = (*kvDuration)(nil)
+var _ ILogKeyValuePair = (*kvTime)(nil)
+var _ ILogKeyValuePair = (*kvStrings)(nil)
+var _ ILogKeyValuePair = (*kvInts)(nil)
+var _ ILogKeyValuePair = (*kvAny)(nil)
+var _ ILogKeyValuePair = (*kvLazy)(nil)
+var _ ILogKeyValuePair = (*kvLazyAny[int])(nil)
 
 /* ----------------------------------------------------------------
  *							T y p e s
@@ -71,7 +88,65 @@ type kvAt struct {
 }
 
 type kvError struct {
-	v error
+	v     error
+	stack []uintptr // only for mlog.ErrStack()
+}
+
+type kvInt64 struct {
+	k string
+	v int64
+}
+
+type kvUint64 struct {
+	k string
+	v uint64
+}
+
+type kvFloat64 struct {
+	k    string
+	v    float64
+	prec int
+}
+
+type kvHex struct {
+	k string
+	v uint64
+}
+
+type kvDuration struct {
+	k string
+	v time.Duration
+}
+
+type kvTime struct {
+	k      string
+	v      time.Time
+	layout string
+}
+
+type kvStrings struct {
+	k string
+	v []string
+}
+
+type kvInts struct {
+	k string
+	v []int
+}
+
+type kvAny struct {
+	k string
+	v any
+}
+
+type kvLazy struct {
+	k  string
+	fn func() string
+}
+
+type kvLazyAny[T any] struct {
+	k  string
+	fn func() T
 }
 
 /* ----------------------------------------------------------------
@@ -123,9 +198,91 @@ func (k *kvAt) String() string {
 	return fmt.Sprintf("At=%s", k.v)
 }
 
-// implements fmt.Stringer for mlog.At()
+// implements fmt.Stringer for mlog.Err() and mlog.ErrStack()
 func (k *kvError) String() string {
-	return fmt.Sprintf("Error=%T=>%s", k.v, k.v)
+	if k.v == nil {
+		return "Error=<nil>"
+	}
+	return "Error=" + describeError(k.v) + formatStack(k.stack)
+}
+
+// implements fmt.Stringer for mlog.Int64()
+func (k *kvInt64) String() string {
+	return k.k + "=" + strconv.FormatInt(k.v, 10)
+}
+
+// implements fmt.Stringer for mlog.Uint64()
+func (k *kvUint64) String() string {
+	return k.k + "=" + strconv.FormatUint(k.v, 10)
+}
+
+// implements fmt.Stringer for mlog.Float64()
+func (k *kvFloat64) String() string {
+	return k.k + "=" + strconv.FormatFloat(k.v, 'f', k.prec, 64)
+}
+
+// implements fmt.Stringer for mlog.Hex()
+func (k *kvHex) String() string {
+	return fmt.Sprintf("%s=0x%X", k.k, k.v)
+}
+
+// implements fmt.Stringer for mlog.Duration()
+func (k *kvDuration) String() string {
+	return k.k + "=" + k.v.String()
+}
+
+// implements fmt.Stringer for mlog.Time()
+func (k *kvTime) String() string {
+	return fmt.Sprintf("%s='%s'", k.k, k.v.Format(k.layout))
+}
+
+// implements fmt.Stringer for mlog.Strings()
+func (k *kvStrings) String() string {
+	var sb strings.Builder
+	sb.WriteString(k.k + "=[")
+	for i, s := range k.v {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString("'" + s + "'")
+	}
+	sb.WriteByte(']')
+	return sb.String()
+}
+
+// implements fmt.Stringer for mlog.Ints()
+func (k *kvInts) String() string {
+	var sb strings.Builder
+	sb.WriteString(k.k + "=[")
+	for i, n := range k.v {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(strconv.Itoa(n))
+	}
+	sb.WriteByte(']')
+	return sb.String()
+}
+
+// implements fmt.Stringer for mlog.Any()
+func (k *kvAny) String() string {
+	return fmt.Sprintf("%s=%+v", k.k, k.v)
+}
+
+// implements fmt.Stringer for mlog.Lazy(). The function runs now.
+func (k *kvLazy) String() string {
+	if k.fn == nil {
+		return k.k + "=<nil>"
+	}
+	return fmt.Sprintf("%s='%s'", k.k, k.fn())
+}
+
+// implements fmt.Stringer for mlog.LazyAny(). The function runs now.
+func (k *kvLazyAny[T]) String() string {
+	if k.fn == nil {
+		return k.k + "=<nil>"
+	}
+	return fmt.Sprintf("%s=%+v", k.k, k.fn())
 }
 
 /* ----------------------------------------------------------------
@@ -167,9 +324,102 @@ func At() ILogKeyValuePair {
 	return &kvAt{RetrieveCallerInfo(FRAMENR_THIS + 1)}
 }
 
-// log an error by its type and message
+// log an error by its type and message followed by those of its
+// causes (the unwrap chain and the members of joined errors)
 func Err(err error) ILogKeyValuePair {
-	return &kvError{err}
+	return &kvError{err, nil}
+}
+
+// log an error like Err() followed by a call stack, one frame per line.
+// It is the stack recorded by WrapE() if any, else the caller's.
+func ErrStack(err error) ILogKeyValuePair {
+	stack := tracedStack(err)
+	if stack == nil && err != nil {
+		pcs := make([]uintptr, maxStackDepth)
+		stack = pcs[:runtime.Callers(2, pcs)]
+	}
+	return &kvError{err, stack}
+}
+
+// log the 64-bit integer key=value pair
+func Int64(key string, value int64) ILogKeyValuePair {
+	return &kvInt64{key, value}
+}
+
+// log the unsigned 64-bit integer key=value pair
+func Uint64(key string, value uint64) ILogKeyValuePair {
+	return &kvUint64{key, value}
+}
+
+// log the floating point key=value pair. The optional precision is
+// the number of decimals, by default as many as needed.
+func Float64(key string, value float64, precision ...int) ILogKeyValuePair {
+	prec := -1
+	if len(precision) != 0 {
+		prec = precision[0]
+	}
+	return &kvFloat64{key, value, prec}
+}
+
+// log the unsigned integer as an hexadecimal key=0x... pair
+func Hex(key string, value uint64) ILogKeyValuePair {
+	return &kvHex{key, value}
+}
+
+// log the duration key=value pair, i.e. Elapsed=1.5s
+func Duration(key string, value time.Duration) ILogKeyValuePair {
+	return &kvDuration{key, value}
+}
+
+// log the time key=value pair. The optional layout defaults to
+// time.RFC3339.
+func Time(key string, value time.Time, layout ...string) ILogKeyValuePair {
+	fmtLayout := time.RFC3339
+	if len(layout) != 0 {
+		fmtLayout = layout[0]
+	}
+	return &kvTime{key, value, fmtLayout}
+}
+
+// log the string slice as key=['a','b']
+func Strings(key string, value []string) ILogKeyValuePair {
+	return &kvStrings{key, value}
+}
+
+// log the integer slice as key=[1,2]
+func Ints(key string, value []int) ILogKeyValuePair {
+	return &kvInts{key, value}
+}
+
+// log any value as key=value using the %+v format
+func Any(key string, value any) ILogKeyValuePair {
+	return &kvAny{key, value}
+}
+
+// log the string produced by fn as key='value'. The function only runs
+// if the level lets the entry through and, with OverflowDropBelow, the
+// asynchronous queue has room for it, so it may be expensive.
+func Lazy(key string, fn func() string) ILogKeyValuePair {
+	return &kvLazy{key, fn}
+}
+
+// log the value produced by fn like Any(). The function only runs
+// if the entry is actually written, so it may be expensive.
+func LazyAny[T any](key string, fn func() T) ILogKeyValuePair {
+	return &kvLazyAny[T]{key, fn}
+}
+
+// splits a rendered tag into its key and value, unquoting 'value'.
+// False if it has no key.
+func splitRendered(rendered string) (string, string, bool) {
+	key, value, found := strings.Cut(rendered, "=")
+	if !found {
+		return "", rendered, false
+	}
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		value = value[1 : len(value)-1]
+	}
+	return key, value, true
 }
 
 /* ----------------------------------------------------------------
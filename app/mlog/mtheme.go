@@ -0,0 +1,271 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Color themes for the main logger output. When it goes to a terminal
+ * the level tags and tag keys are colorized with a 16-color, 256-color
+ * or truecolor theme depending on the terminal, NO_COLOR and
+ * FORCE_COLOR. Files and pipes stay plain.
+ *-----------------------------------------------------------------*/
+package mlog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+/* ----------------------------------------------------------------
+ *							G l o b a l s
+ *-----------------------------------------------------------------*/
+
+// Color depth enumeration
+const (
+	ColorDepthNone ColorDepth = iota // plain text
+	ColorDepth16                     // the 16 basic ANSI colors
+	ColorDepth256                    // the xterm 256-color palette
+	ColorDepthTrue                   // 24-bit colors
+)
+
+var (
+	// the palette of the ColorConsole
+	Theme16 = Theme{
+		Trace:     ColorLightPurple,
+		Debug:     ColorBrown,
+		Info:      ColorGreen,
+		Warning:   ColorYellow,
+		Error:     ColorPurple,
+		Fatal:     ColorRed,
+		Key:       ColorCyan,
+		FatalFace: face2,
+	}
+
+	// for terminals with 256 colors
+	Theme256 = Theme{
+		Trace:     Color256(141),
+		Debug:     Color256(180),
+		Info:      Color256(114),
+		Warning:   Color256(221),
+		Error:     Color256(203),
+		Fatal:     Color256(196),
+		Key:       Color256(75),
+		FatalFace: face2,
+	}
+
+	// for terminals with 24-bit colors
+	ThemeTrueColor = Theme{
+		Trace:     ColorRGB(175, 135, 255),
+		Debug:     ColorRGB(215, 175, 135),
+		Info:      ColorRGB(135, 215, 135),
+		Warning:   ColorRGB(255, 215, 95),
+		Error:     ColorRGB(255, 95, 95),
+		Fatal:     ColorRGB(255, 0, 0),
+		Key:       ColorRGB(95, 175, 255),
+		FatalFace: face2,
+	}
+)
+
+/* ----------------------------------------------------------------
+ *							T y p e s
+ *-----------------------------------------------------------------*/
+
+// ColorDepth is the number of colors a terminal can show
+type ColorDepth int
+
+// Theme holds the colors of the level tags and the tag keys. Empty
+// colors are not printed at all.
+type Theme struct {
+	Trace     Color
+	Debug     Color
+	Info      Color
+	Warning   Color
+	Error     Color
+	Fatal     Color
+	Key       Color
+	Bold      bool   // level tags in bold
+	Underline bool   // tag keys underlined
+	FatalFace string // appended to the Fatal entries of a ColorConsole
+}
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// LevelColor returns the color of the level tag.
+func (t *Theme) LevelColor(level LogLevel) Color {
+	switch level {
+	case LevelTrace:
+		return t.Trace
+	case LevelDebug:
+		return t.Debug
+	case LevelInfo:
+		return t.Info
+	case LevelWarning:
+		return t.Warning
+	case LevelError:
+		return t.Error
+	default:
+		return t.Fatal
+	}
+}
+
+// the escape codes starting a level tag
+func (t *Theme) levelStyle(level LogLevel) string {
+	style := string(t.LevelColor(level))
+	if t.Bold {
+		style += string(BoldOn)
+	}
+	return style
+}
+
+// the escape codes starting a tag key
+func (t *Theme) keyStyle() string {
+	style := string(t.Key)
+	if t.Underline {
+		style += string(UnderlineOn)
+	}
+	return style
+}
+
+// the reset code if a style was started
+func (t *Theme) reset(style string) string {
+	if style == "" {
+		return ""
+	}
+	return string(ColorReset)
+}
+
+// writes the rendered tags, each after a space, highlighting the keys
+func (t *Theme) writeTags(sb *strings.Builder, rendered []string) {
+	keyStyle := t.keyStyle()
+	for _, s := range rendered {
+		sb.WriteString(" ")
+		if key, value, found := strings.Cut(s, "="); found {
+			sb.WriteString(keyStyle + key + t.reset(keyStyle) + "=" + value)
+		} else {
+			sb.WriteString(s)
+		}
+	}
+}
+
+// colorizes the level tag and tag keys of an entry
+func (t *Theme) entry(level LogLevel, message string, rendered []string) string {
+	var sb strings.Builder
+	tag := levelTag(level)
+	style := t.levelStyle(level)
+	sb.WriteString(style + tag[:len(tag)-1] + t.reset(style) + " ")
+	sb.WriteString(message)
+	t.writeTags(&sb, rendered)
+	return sb.String()
+}
+
+// SetTheme colorizes the main output with the theme, or not at all if
+// nil. SetOutput() picks the theme anew.
+func (l *Logger) SetTheme(theme *Theme) {
+	l.outMu.Lock()
+	defer l.outMu.Unlock()
+
+	if theme != nil {
+		copied := *theme
+		theme = &copied
+	}
+	l.theme = theme
+}
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// SetTheme colorizes the output of the default logger with the theme,
+// or not at all if nil.
+func SetTheme(theme *Theme) {
+	std.SetTheme(theme)
+}
+
+// Color256 returns the foreground color of the xterm 256-color palette.
+func Color256(n uint8) Color {
+	return Color(fmt.Sprintf("\u001b[38;5;%dm", n))
+}
+
+// ColorRGB returns a 24-bit foreground color.
+func ColorRGB(r, g, b uint8) Color {
+	return Color(fmt.Sprintf("\u001b[38;2;%d;%d;%dm", r, g, b))
+}
+
+// ThemeFor returns a copy of the built-in theme for the color depth,
+// nil for ColorDepthNone.
+func ThemeFor(depth ColorDepth) *Theme {
+	var theme Theme
+	switch depth {
+	case ColorDepth16:
+		theme = Theme16
+	case ColorDepth256:
+		theme = Theme256
+	case ColorDepthTrue:
+		theme = ThemeTrueColor
+	default:
+		return nil
+	}
+	return &theme
+}
+
+// DetectColorDepth tells the colors that output to f may use. NO_COLOR
+// disables them and FORCE_COLOR (1, 2 or 3 for 16, 256 or truecolor)
+// forces them even if f is not a terminal. Otherwise terminals get
+// the depth announced by COLORTERM and TERM.
+func DetectColorDepth(f *os.File) ColorDepth {
+	if os.Getenv("NO_COLOR") != "" {
+		return ColorDepthNone
+	}
+
+	force := os.Getenv("FORCE_COLOR")
+	switch strings.ToLower(force) {
+	case "":
+		if f == nil || !isTerminal(f) || os.Getenv("TERM") == "dumb" {
+			return ColorDepthNone
+		}
+	case "0", "false":
+		return ColorDepthNone
+	case "1", "true":
+		return ColorDepth16
+	case "2":
+		return ColorDepth256
+	case "3":
+		return ColorDepthTrue
+	}
+
+	colorTerm := strings.ToLower(os.Getenv("COLORTERM"))
+	switch {
+	case colorTerm == "truecolor" || colorTerm == "24bit":
+		return ColorDepthTrue
+	case strings.Contains(os.Getenv("TERM"), "256color"):
+		return ColorDepth256
+	default:
+		return ColorDepth16
+	}
+}
+
+// the theme for output to w: a terminal (even when timestamped by a
+// customLogWriter) gets colors, anything else none. FORCE_COLOR only
+// applies to stdout and stderr, never to files such as the log file.
+func themeForWriter(w io.Writer) *Theme {
+	if cw, ok := w.(*customLogWriter); ok {
+		w = cw.writer
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return nil
+	}
+	if f != os.Stdout && f != os.Stderr && !isTerminal(f) {
+		return nil
+	}
+	return ThemeFor(DetectColorDepth(f))
+}
+
+// whether f is a character device, i.e. a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
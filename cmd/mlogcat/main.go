@@ -0,0 +1,353 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * mlogcat parses, filters and pretty-prints mlog files.
+ *
+ *	mlogcat -level warn -tag User=john app.log
+ *	mlogcat -sessions app.log
+ *	mlogcat -session -1 -since 1h app.log
+ *	mlogcat -f -pkg 'github.com/acme/app/*' app.log
+ *-----------------------------------------------------------------*/
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lordofscripts/goapp/app/mlog"
+	"github.com/lordofscripts/goapp/app/mlog/mlogparse"
+)
+
+/* ----------------------------------------------------------------
+ *							G l o b a l s
+ *-----------------------------------------------------------------*/
+
+const (
+	// how often a followed file is checked for growth
+	pollInterval = 250 * time.Millisecond
+)
+
+/* ----------------------------------------------------------------
+ *							T y p e s
+ *-----------------------------------------------------------------*/
+
+// the -tag key=value flags
+type tagFlags []mlogparse.Tag
+
+// writes the selected entries
+type printer struct {
+	w      *bufio.Writer
+	filter *mlogparse.Filter
+	theme  *mlog.Theme // nil for plain output
+}
+
+/* ----------------------------------------------------------------
+ *							M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// implements flag.Value
+func (t *tagFlags) String() string {
+	return fmt.Sprint(*t)
+}
+
+// implements flag.Value
+func (t *tagFlags) Set(s string) error {
+	key, value, _ := strings.Cut(s, "=")
+	if key == "" {
+		return errors.New("expected key=value or key")
+	}
+	*t = append(*t, mlogparse.Tag{Key: key, Value: value})
+	return nil
+}
+
+// writes the entry if it passes the filter
+func (p *printer) print(e *mlogparse.Entry) {
+	if !p.filter.Match(e) {
+		return
+	}
+	if p.theme == nil {
+		p.w.WriteString(e.Raw + "\n")
+		for _, extra := range e.Extra {
+			p.w.WriteString(extra + "\n")
+		}
+		return
+	}
+
+	switch e.Kind {
+	case mlogparse.KindBegin, mlogparse.KindEnd:
+		fmt.Fprint(p.w, mlog.BoldOn, e.Raw, mlog.ColorReset, "\n")
+
+	case mlogparse.KindText:
+		p.w.WriteString(e.Raw + "\n")
+
+	default:
+		color := p.theme.LevelColor(e.Level)
+		if e.Catheter {
+			color = mlog.ColorCyan
+		}
+		if e.TimeText != "" {
+			p.w.WriteString(e.TimeText + " ")
+		}
+		p.w.WriteString(e.Prefix)
+		fmt.Fprintf(p.w, "%s%s%s %s", color, e.LevelTag, mlog.ColorReset, e.Message)
+		for _, t := range e.Tags {
+			_, value, _ := strings.Cut(t.Raw, "=")
+			fmt.Fprintf(p.w, " %s%s%s=%s", p.theme.Key, t.Key, mlog.ColorReset, value)
+		}
+		p.w.WriteString("\n")
+		for _, extra := range e.Extra {
+			fmt.Fprintf(p.w, "%s%s%s\n", color, extra, mlog.ColorReset)
+		}
+	}
+}
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+func main() {
+	var tags tagFlags
+	level := flag.String("level", "", "minimum level: trace, debug, info, warn, error or fatal")
+	since := flag.String("since", "", "entries at or after a time (2006-01-02[ 15:04:05] or RFC3339) or a duration ago (i.e. 1h)")
+	until := flag.String("until", "", "entries at or before a time or a duration ago")
+	pkg := flag.String("pkg", "", "package of the call site, '*' matches anything (slashes too), i.e. '*/crypto'")
+	flag.Var(&tags, "tag", "entries with the tag key=value, or just key (repeatable)")
+	session := flag.Int("session", 0, "only that session (1 is the first, -1 the last)")
+	sessions := flag.Bool("sessions", false, "list the sessions instead of the entries")
+	followFile := flag.Bool("f", false, "follow the file as it grows")
+	colorMode := flag.String("color", "auto", "colorize levels: auto, always or never")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [file ...]\n\nWith no file, or -, reads stdin.\n\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	filter := &mlogparse.Filter{Package: *pkg, Tags: tags}
+	var err error
+	if *level != "" {
+		if filter.MinLevel, err = mlog.ParseLevel(*level); err != nil {
+			fail(err)
+		}
+	}
+	if filter.Since, err = parseTime(*since); err != nil {
+		fail(err)
+	}
+	if filter.Until, err = parseTime(*until); err != nil {
+		fail(err)
+	}
+
+	out := &printer{w: bufio.NewWriter(os.Stdout), filter: filter, theme: themeFor(*colorMode)}
+	defer out.w.Flush()
+
+	files := flag.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	if *followFile && (len(files) != 1 || files[0] == "-" || *session != 0 || *sessions) {
+		fail(errors.New("-f takes a single file and no -session or -sessions"))
+	}
+
+	for _, name := range files {
+		switch {
+		case *followFile:
+			err = follow(name, out)
+		case *sessions:
+			err = withFile(name, func(r io.Reader) error { return listSessions(r, out.w) })
+		case *session != 0:
+			err = withFile(name, func(r io.Reader) error { return printSession(r, *session, out) })
+		default:
+			err = withFile(name, func(r io.Reader) error { return printAll(r, out) })
+		}
+		if err != nil {
+			out.w.Flush()
+			fail(err)
+		}
+	}
+}
+
+// prints the error and exits
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "mlogcat: %v\n", err)
+	os.Exit(1)
+}
+
+// calls fn with the named file or stdin for "-"
+func withFile(name string, fn func(io.Reader) error) error {
+	if name == "-" {
+		return fn(os.Stdin)
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return fn(f)
+}
+
+// prints every selected entry
+func printAll(r io.Reader, out *printer) error {
+	p := mlogparse.NewParser(r)
+	for {
+		e, err := p.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		out.print(e)
+	}
+}
+
+// prints the selected entries of a single session
+func printSession(r io.Reader, number int, out *printer) error {
+	sessions, err := mlogparse.ReadSessions(r)
+	if err != nil {
+		return err
+	}
+
+	var selected *mlogparse.Session = nil
+	if number < 0 && -number <= len(sessions) {
+		selected = sessions[len(sessions)+number]
+	}
+	for _, s := range sessions {
+		if number > 0 && s.Number == number {
+			selected = s
+		}
+	}
+	if selected == nil {
+		return fmt.Errorf("there is no session %d", number)
+	}
+
+	for _, e := range selected.Entries {
+		out.print(e)
+	}
+	return nil
+}
+
+// prints a line per session with its time span and level counts
+func listSessions(r io.Reader, w io.Writer) error {
+	sessions, err := mlogparse.ReadSessions(r)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range sessions {
+		var first, last time.Time
+		counts := make(map[string]int)
+		for _, e := range s.Entries {
+			if !e.Time.IsZero() {
+				if first.IsZero() {
+					first = e.Time
+				}
+				last = e.Time
+			}
+			if e.Kind == mlogparse.KindEntry {
+				counts[e.LevelTag]++
+			}
+		}
+
+		status := "ended"
+		if !s.Ended {
+			status = "unfinished"
+		}
+		fmt.Fprintf(w, "#%d\t%d entries\t%s", s.Number, len(s.Entries), status)
+		if !first.IsZero() {
+			fmt.Fprintf(w, "\t%s .. %s", first.Format("2006-01-02 15:04:05"), last.Format("2006-01-02 15:04:05"))
+		}
+		for _, tag := range []string{"[DIE]", "[ERR]", "[WRN]"} {
+			if counts[tag] > 0 {
+				fmt.Fprintf(w, "\t%s %d", tag, counts[tag])
+			}
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+// prints the selected entries as the file grows. It starts over if
+// the file is truncated or replaced (rotated).
+func follow(name string, out *printer) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer func() { f.Close() }()
+
+	p := mlogparse.NewParser(f)
+	p.Follow(true)
+	for {
+		e, err := p.Next()
+		if err == nil {
+			out.print(e)
+			continue
+		}
+		if err != io.EOF {
+			return err
+		}
+
+		out.w.Flush()
+		time.Sleep(pollInterval)
+
+		offset, _ := f.Seek(0, io.SeekCurrent)
+		opened, _ := f.Stat()
+		current, err := os.Stat(name)
+		if err != nil || opened == nil || os.SameFile(opened, current) && current.Size() >= offset {
+			continue
+		}
+
+		// what was written before the switch
+		for e, err := p.Next(); err == nil; e, err = p.Next() {
+			out.print(e)
+		}
+		f.Close()
+		if f, err = os.Open(name); err != nil {
+			return err
+		}
+		p = mlogparse.NewParser(f)
+		p.Follow(true)
+	}
+}
+
+// a local date, date and time, RFC3339 time or a duration ago
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", s)
+}
+
+// the colors by mode: auto colorizes terminals only (see
+// mlog.DetectColorDepth), always at least with 16 colors.
+func themeFor(mode string) *mlog.Theme {
+	depth := mlog.DetectColorDepth(os.Stdout)
+	switch mode {
+	case "never":
+		return nil
+	case "always":
+		if depth == mlog.ColorDepthNone {
+			depth = mlog.ColorDepth16
+		}
+	}
+	return mlog.ThemeFor(depth)
+}
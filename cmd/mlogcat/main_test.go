@@ -0,0 +1,48 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Lord of Scripts
+ *							   goApp
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Tests of the printer of mlogcat, plain and colorized.
+ *-----------------------------------------------------------------*/
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/lordofscripts/goapp/app/mlog"
+	"github.com/lordofscripts/goapp/app/mlog/mlogparse"
+)
+
+/* ----------------------------------------------------------------
+ *							F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// the output of printAll() for the input
+func printed(t *testing.T, input string, theme *mlog.Theme) string {
+	var sb strings.Builder
+	out := &printer{w: bufio.NewWriter(&sb), filter: &mlogparse.Filter{}, theme: theme}
+	if err := printAll(strings.NewReader(input), out); err != nil {
+		t.Fatal(err)
+	}
+	out.w.Flush()
+	return sb.String()
+}
+
+func TestPrintMarkers(t *testing.T) {
+	const begin = "[BEG]\t> > > >   T h e   B e g i n n i n g   < < < <"
+	const end = "[END]\t> > > >   T h e   E n d   < < < <"
+	input := "\uFEFF" + begin + "\n" + end + "\n"
+
+	if got := printed(t, input, nil); got != begin+"\n"+end+"\n" {
+		t.Errorf("plain %q", got)
+	}
+
+	want := string(mlog.BoldOn) + begin + string(mlog.ColorReset) + "\n" +
+		string(mlog.BoldOn) + end + string(mlog.ColorReset) + "\n"
+	if got := printed(t, input, mlog.ThemeFor(mlog.ColorDepth16)); got != want {
+		t.Errorf("colorized %q, want %q", got, want)
+	}
+}